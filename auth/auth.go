@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Strovala/crackview/session"
+)
+
+// Roles of authenticated clients, interviewer and candidate match session roles
+const (
+	RoleAdmin       = "admin"
+	RoleInterviewer = session.RoleInterviewer
+	RoleCandidate   = session.RoleCandidate
+)
+
+// Errors of authentication
+var (
+	ErrUnauthenticated = errors.New("Authentication required")
+	ErrInvalidToken    = errors.New("Invalid or expired token")
+	ErrUnknownRole     = errors.New("Unknown role")
+	ErrInvalidTTL      = errors.New("Token TTL has to be positive")
+)
+
+// DefaultMaxTokenTTL caps lifetime of signed tokens when config does not set it
+const DefaultMaxTokenTTL = 24 * time.Hour
+
+// Key is static API key from config
+type Key struct {
+	Name string `mapstructure:"name"`
+	Key  string `mapstructure:"key"`
+	Role string `mapstructure:"role"`
+}
+
+// Config is authentication config, Secret signs session tokens and
+// MaxTokenTTL caps their lifetime in minutes
+type Config struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Secret      string `mapstructure:"secret"`
+	Keys        []Key  `mapstructure:"keys"`
+	MaxTokenTTL int    `mapstructure:"maxTokenTTL"`
+}
+
+// Claims identify authenticated client, tokens with session id are valid only for that session
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// ValidRole checks if role is one of known roles
+func ValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleInterviewer || role == RoleCandidate
+}
+
+// Authenticator checks API keys and signs and verifies session tokens
+type Authenticator struct {
+	secret []byte
+	keys   []Key
+	maxTTL time.Duration
+}
+
+// New creates authenticator from config
+func New(config Config) (*Authenticator, error) {
+	if config.Secret == "" {
+		return nil, errors.New("auth secret is not configured")
+	}
+	for _, key := range config.Keys {
+		if key.Key == "" || !ValidRole(key.Role) {
+			return nil, errors.New("auth key " + key.Name + " has no key or unknown role")
+		}
+	}
+	maxTTL := time.Duration(config.MaxTokenTTL) * time.Minute
+	if maxTTL <= 0 {
+		maxTTL = DefaultMaxTokenTTL
+	}
+	return &Authenticator{secret: []byte(config.Secret), keys: config.Keys, maxTTL: maxTTL}, nil
+}
+
+// TTL returns lifetime of token with requested ttl capped by configured maximum,
+// every token has to expire so ttl has to be positive
+func (a *Authenticator) TTL(ttl time.Duration) (time.Duration, error) {
+	if ttl <= 0 {
+		return 0, ErrInvalidTTL
+	}
+	if ttl > a.maxTTL {
+		return a.maxTTL, nil
+	}
+	return ttl, nil
+}
+
+// APIKey returns claims of static API key
+func (a *Authenticator) APIKey(apiKey string) (*Claims, error) {
+	for _, key := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(apiKey)) == 1 {
+			return &Claims{Subject: key.Name, Role: key.Role}, nil
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// Sign creates token signed with HMAC-SHA256 which carries claims
+func (a *Authenticator) Sign(claims *Claims) (string, error) {
+	if !ValidRole(claims.Role) {
+		return "", ErrUnknownRole
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + a.signature(encoded), nil
+}
+
+// Verify checks signature, role and expiration of token and returns its claims,
+// tokens without expiration or living longer than maximum TTL are rejected
+func (a *Authenticator) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(a.signature(parts[0]))) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := &Claims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !ValidRole(claims.Role) {
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	if claims.ExpiresAt == 0 || now.Unix() > claims.ExpiresAt || claims.ExpiresAt > now.Add(a.maxTTL).Unix() {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (a *Authenticator) signature(payload string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
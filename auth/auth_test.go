@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newAuthenticator(t *testing.T, secret string) *Authenticator {
+	a, err := New(Config{Secret: secret, Keys: []Key{{Name: "ci", Key: "ci-key", Role: RoleAdmin}}, MaxTokenTTL: 60})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return a
+}
+
+// signRaw signs payload without checks of Sign
+func signRaw(a *Authenticator, payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + a.signature(encoded)
+}
+
+func TestSignVerify(t *testing.T) {
+	a := newAuthenticator(t, "secret")
+	valid := &Claims{Subject: "ana", Role: RoleInterviewer, SessionID: "s1", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+	token, err := a.Sign(valid)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	expired, err := a.Sign(&Claims{Subject: "ana", Role: RoleCandidate, ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	other, err := newAuthenticator(t, "other").Sign(valid)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	forged, err := a.Sign(&Claims{Subject: "ana", Role: RoleAdmin, ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	withoutExpiry, err := a.Sign(&Claims{Subject: "ana", Role: RoleAdmin})
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	tooLong, err := a.Sign(&Claims{Subject: "ana", Role: RoleAdmin, ExpiresAt: time.Now().Add(2 * time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+	exp := time.Now().Add(time.Minute).Unix()
+	forgedRole := signRaw(a, `{"sub":"ana","role":"owner","exp":`+strconv.FormatInt(exp, 10)+`}`)
+	withoutRole := signRaw(a, `{"sub":"ana","exp":`+strconv.FormatInt(exp, 10)+`}`)
+
+	tests := []struct {
+		name  string
+		token string
+		want  *Claims
+	}{
+		{name: "valid", token: token, want: valid},
+		{name: "expired", token: expired},
+		{name: "signed with other secret", token: other},
+		{name: "payload of other token", token: strings.Split(forged, ".")[0] + "." + parts[1]},
+		{name: "without expiry", token: withoutExpiry},
+		{name: "expiry after max ttl", token: tooLong},
+		{name: "unknown role", token: forgedRole},
+		{name: "without role", token: withoutRole},
+		{name: "without signature", token: parts[0]},
+		{name: "extra part", token: token + ".x"},
+		{name: "empty", token: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := a.Verify(tt.token)
+			if tt.want == nil {
+				if err != ErrInvalidToken {
+					t.Errorf("Verify error = %v, want %v", err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify error: %v", err)
+			}
+			if !reflect.DeepEqual(claims, tt.want) {
+				t.Errorf("Verify = %+v, want %+v", claims, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignUnknownRole(t *testing.T) {
+	if _, err := newAuthenticator(t, "secret").Sign(&Claims{Subject: "ana", Role: "owner"}); err != ErrUnknownRole {
+		t.Errorf("Sign error = %v, want %v", err, ErrUnknownRole)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	a := newAuthenticator(t, "secret")
+	claims, err := a.APIKey("ci-key")
+	if err != nil {
+		t.Fatalf("APIKey error: %v", err)
+	}
+	if claims.Subject != "ci" || claims.Role != RoleAdmin {
+		t.Errorf("APIKey = %+v, want ci admin", claims)
+	}
+	for _, key := range []string{"", "ci", "ci-key "} {
+		if _, err := a.APIKey(key); err != ErrInvalidToken {
+			t.Errorf("APIKey(%q) error = %v, want %v", key, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		ttl     time.Duration
+		want    time.Duration
+		wantErr error
+	}{
+		{ttl: 10 * time.Minute, want: 10 * time.Minute},
+		{ttl: time.Hour, want: time.Hour},
+		{ttl: 2 * time.Hour, want: time.Hour},
+		{ttl: 0, wantErr: ErrInvalidTTL},
+		{ttl: -time.Minute, wantErr: ErrInvalidTTL},
+	}
+	a := newAuthenticator(t, "secret")
+	for _, tt := range tests {
+		got, err := a.TTL(tt.ttl)
+		if err != tt.wantErr || got != tt.want {
+			t.Errorf("TTL(%v) = %v, %v, want %v, %v", tt.ttl, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "valid", config: Config{Secret: "s", Keys: []Key{{Name: "a", Key: "k", Role: RoleCandidate}}}},
+		{name: "no secret", config: Config{}, wantErr: true},
+		{name: "key without key", config: Config{Secret: "s", Keys: []Key{{Name: "a", Role: RoleAdmin}}}, wantErr: true},
+		{name: "key with unknown role", config: Config{Secret: "s", Keys: []Key{{Name: "a", Key: "k", Role: "owner"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && a.maxTTL != DefaultMaxTokenTTL {
+				t.Errorf("maxTTL = %v, want default %v", a.maxTTL, DefaultMaxTokenTTL)
+			}
+		})
+	}
+}
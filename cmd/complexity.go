@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	complexityLang     string
+	complexityMinSize  int
+	complexityMaxSize  int
+	complexityRepeats  int
+	complexityDuration time.Duration
+	complexitySeed     int64
+	complexityCompile  []string
+	complexityRun      []string
+)
+
+func init() {
+	complexityCmd.Flags().StringVar(&complexityLang, "lang", "", "language of solution (default is taken from file extension)")
+	complexityCmd.Flags().IntVar(&complexityMinSize, "min-size", problem.DefaultComplexityMinSize, "size of smallest input")
+	complexityCmd.Flags().IntVar(&complexityMaxSize, "max-size", problem.DefaultComplexityMaxSize, "size of largest input")
+	complexityCmd.Flags().IntVar(&complexityRepeats, "repeats", problem.DefaultComplexityRepeats, "number of runs per size, fastest one is measured")
+	complexityCmd.Flags().DurationVar(&complexityDuration, "duration", 0, "stop after duration even if not every size was run")
+	complexityCmd.Flags().Int64Var(&complexitySeed, "seed", 0, "seed of random generator to reproduce inputs")
+	complexityCmd.Flags().StringArrayVar(&complexityCompile, "compile-flag", nil, "compiler flag allowed by flags config, can be repeated")
+	complexityCmd.Flags().StringArrayVar(&complexityRun, "run-flag", nil, "run flag allowed by flags config, can be repeated")
+	rootCmd.AddCommand(complexityCmd)
+}
+
+var complexityCmd = &cobra.Command{
+	Use:   "complexity <problem dir> <solution>",
+	Short: "Estimate time complexity of solution",
+	Long: `Run solution on inputs generated by generator declared in problem.yaml with
+sizes doubling from --min-size to --max-size and fit its run time against
+O(1), O(log n), O(n), O(n log n), O(n^2) and O(2^n). Size is number of elements
+of every array, set and map argument, length of every string argument and value
+of every int argument marked as scaled in generator. Only call of solution is
+timed, startup and building of input are not. Java inputs are compiled into
+main method whose size is limited, so --max-size is capped at 2048 for java.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := problem.Load(args[0])
+		if err != nil {
+			return err
+		}
+		solution, err := ioutil.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		lang := complexityLang
+		if lang == "" {
+			if lang, err = execution.LanguageOf(args[1]); err != nil {
+				return err
+			}
+		}
+		estimate, err := p.Complexity(problem.ComplexityOptions{
+			Lang:     lang,
+			Solution: string(solution),
+			Flags:    requestedFlags(complexityCompile, complexityRun),
+			MinSize:  complexityMinSize,
+			MaxSize:  complexityMaxSize,
+			Repeats:  complexityRepeats,
+			Duration: complexityDuration,
+			Seed:     complexitySeed,
+			Logger:   zap.NewNop(),
+		})
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "SIZE\tRUN TIME\tVERDICT")
+		for _, m := range estimate.Measurements {
+			fmt.Fprintf(w, "%v\t%vms\t%v\n", m.Size, m.RunTime, m.Verdict)
+		}
+		fmt.Fprintln(w, "\nCLASS\tR2\t")
+		for _, f := range estimate.Fits {
+			fmt.Fprintf(w, "%v\t%.3f\t\n", f.Class, f.R2)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("\nestimated complexity: %v\n", estimate.Class)
+		return nil
+	},
+}
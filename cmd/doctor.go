@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Strovala/crackview/doctor"
+	"github.com/Strovala/crackview/execution"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that host can compile and run solutions in every configured language",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		langs, err := execution.Languages(viper.GetStringSlice("languages"))
+		if err != nil {
+			return err
+		}
+		checks := doctor.Run(langs, viper.GetString("workdir"))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "LANGUAGE\tCHECK\tSTATUS\tDETAIL")
+		for _, check := range checks {
+			lang := check.Lang
+			if lang == "" {
+				lang = "-"
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", lang, check.Name, check.Status, check.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if doctor.Failed(checks) {
+			cmd.SilenceUsage = true
+			return errors.New("some checks failed")
+		}
+		return nil
+	},
+}
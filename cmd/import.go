@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Strovala/crackview/importer"
+	"github.com/Strovala/crackview/problem"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	importFormat   string
+	importID       string
+	importExamples int
+	importOutput   string
+)
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "format of packages, polygon or json (default is detected)")
+	importCmd.Flags().StringVar(&importID, "id", "", "id of imported problem, only with single package (default is taken from package)")
+	importCmd.Flags().IntVar(&importExamples, "examples", 0, "number of first tests shown to candidate when package does not mark them (default 1)")
+	importCmd.Flags().StringVarP(&importOutput, "output", "o", "", "directory problems are written to (default is problems from config)")
+	rootCmd.AddCommand(importCmd)
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <package>...",
+	Short: "Import problems from Polygon style directories or json files",
+	Long: `Import problems into problem directory. Polygon style package is directory
+with tests/*.in (or tests/NN) inputs and *.ans (or *.a) answers and optional
+problem.xml and statements, every input line is an argument. Json package has
+id, title, statement, signature, examples and tests where test input is list
+of arguments. Types of arguments and result are inferred from values when
+signature is not declared. Packages which fail are reported and skipped.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importID != "" && len(args) > 1 {
+			return fmt.Errorf("--id can be used only with single package")
+		}
+		output := importOutput
+		if output == "" {
+			output = viper.GetString("problems")
+		}
+		var failed []string
+		for _, path := range args {
+			p, err := importProblem(path, output)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", path, err)
+				failed = append(failed, path)
+				continue
+			}
+			fmt.Printf("%v: imported %v (%v) => %v with %v tests\n",
+				path, p.ID, strings.Join(p.Signature.Args, ", "), p.Signature.Returns, len(p.Tests))
+		}
+		if len(failed) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%v of %v packages failed to import", len(failed), len(args))
+		}
+		return nil
+	},
+}
+
+// importProblem imports package into output directory and checks that imported
+// problem loads, problem which does not load is removed
+func importProblem(path, output string) (*problem.Problem, error) {
+	p, err := importer.Import(path, importer.Options{
+		Format:   importFormat,
+		ID:       importID,
+		Examples: importExamples,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(output, p.ID)
+	if err := p.Save(dir); err != nil {
+		return nil, err
+	}
+	loaded, err := problem.Load(dir)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	return loaded, nil
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/report"
+	"github.com/Strovala/crackview/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	reportFormat string
+	reportOutput string
+)
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFormat, "format", report.FormatHTML, "report format, html or markdown")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "file to write report to (default is stdout)")
+	rootCmd.AddCommand(reportCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report <session>",
+	Short: "Render interview session report",
+	Long: `Render interview session report with problem statements, final code,
+executions, pass rates over time and interviewer notes.
+Store is locked while server is running, so stop the server or use
+the /sessions/{id}/report endpoint instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := store.Open(viper.GetString("store"))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		problems, err := problem.LoadBank(viper.GetString("problems"))
+		if err != nil {
+			return err
+		}
+		rubric, err := loadRubric()
+		if err != nil {
+			return err
+		}
+		data, err := report.Build(db, problems, rubric, args[0])
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if reportOutput != "" {
+			file, err := os.Create(reportOutput)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		return data.Render(out, reportFormat)
+	},
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Strovala/crackview/logging"
+	"github.com/Strovala/crackview/runner"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 var configFile string
@@ -27,6 +30,22 @@ func initConfig() {
 		fmt.Printf("unable to read config: %v\n", err)
 		os.Exit(1)
 	}
+	initLogger()
+}
+
+// initLogger replaces global logger with one configured by log config
+func initLogger() {
+	config := logging.Config{}
+	if err := viper.UnmarshalKey("log", &config); err != nil {
+		fmt.Printf("unable to read log config: %v\n", err)
+		os.Exit(1)
+	}
+	logger, err := logging.New(config)
+	if err != nil {
+		fmt.Printf("unable to create logger: %v\n", err)
+		os.Exit(1)
+	}
+	zap.ReplaceGlobals(logger)
 }
 
 var rootCmd = &cobra.Command{
@@ -39,7 +58,11 @@ var rootCmd = &cobra.Command{
 
 // Execute executes root command
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// every command which runs java code can start warm JVMs
+	runner.CloseJVMPool()
+	_ = zap.L().Sync()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/runner"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	runLang      string
+	runInput     string
+	runReturns   string
+	runTimeLimit int
+	runVerbose   bool
+	runCompile   []string
+	runRun       []string
+)
+
+func init() {
+	runCmd.Flags().StringVar(&runLang, "lang", "", "language of solution (default is taken from file extension)")
+	runCmd.Flags().StringVar(&runInput, "input", "", "file with solution arguments, one value with its type per line")
+	runCmd.Flags().StringVar(&runReturns, "returns", runner.DefaultReturns, "type of value returned by solution")
+	runCmd.Flags().IntVar(&runTimeLimit, "time-limit", 0, "time limit in milliseconds (default is timeLimit from config)")
+	runCmd.Flags().BoolVarP(&runVerbose, "verbose", "v", false, "log generation and execution steps")
+	runCmd.Flags().StringArrayVar(&runCompile, "compile-flag", nil, "compiler flag allowed by flags config, can be repeated")
+	runCmd.Flags().StringArrayVar(&runRun, "run-flag", nil, "run flag allowed by flags config, can be repeated")
+	rootCmd.AddCommand(runCmd)
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <solution>",
+	Short: "Run solution locally",
+	Long: `Run solution with arguments from input file without HTTP server.
+Solution output is printed while it runs, followed by returned value and verdict.
+Command fails when verdict is not OK.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		solution, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		lang := runLang
+		if lang == "" {
+			if lang, err = execution.LanguageOf(args[0]); err != nil {
+				return err
+			}
+		}
+		var input []byte
+		if runInput != "" {
+			if input, err = ioutil.ReadFile(runInput); err != nil {
+				return err
+			}
+		}
+		out := cmd.OutOrStdout()
+		logger := zap.NewNop()
+		if runVerbose {
+			logger = zap.L()
+		}
+
+		result, err := runner.Run(&runner.Request{
+			Lang:      lang,
+			Solution:  string(solution),
+			Input:     string(input),
+			Returns:   runReturns,
+			TimeLimit: time.Duration(runTimeLimit) * time.Millisecond,
+			Flags:     requestedFlags(runCompile, runRun),
+			Listener:  printChunk(out, cmd.ErrOrStderr()),
+			Logger:    logger,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nresult:  %v\n", result.Result)
+		fmt.Fprintf(out, "verdict: %v\n", result.Verdict)
+		fmt.Fprintf(out, "time:    compile %vms, run %vms\n", result.CompileTime, result.RunTime)
+		if result.Verdict != execution.VerdictOK {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("solution finished with %v", result.Verdict)
+		}
+		return nil
+	},
+}
+
+// printChunk returns listener which prints solution output as it is produced
+func printChunk(out, errOut io.Writer) execution.OutputListener {
+	return func(chunk execution.Chunk) {
+		if chunk.Stream == execution.Stderr {
+			fmt.Fprint(errOut, chunk.Text)
+			return
+		}
+		fmt.Fprint(out, chunk.Text)
+	}
+}
+
+// requestedFlags returns flags requested on command line, nil when none were
+func requestedFlags(compile, run []string) *execution.Flags {
+	if len(compile) == 0 && len(run) == 0 {
+		return nil
+	}
+	return &execution.Flags{Compile: compile, Run: run}
+}
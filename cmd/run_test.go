@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/runner"
+)
+
+// TestMain runs tests from repository root where templates and problems are
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func requirePython(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not available")
+	}
+}
+
+// writeFile writes file with content to temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCommand(t *testing.T) {
+	requirePython(t)
+	input := writeFile(t, "input.txt", "2 => int\n3 => int\n")
+	tests := []struct {
+		name      string
+		file      string
+		solution  string
+		lang      string
+		input     string
+		timeLimit int
+		run       []string
+		// want are parts of stdout and stderr, wantErr is part of error
+		want    []string
+		wantErr string
+	}{
+		{
+			name:     "ok",
+			file:     "solution.py",
+			solution: "class Solution:\n    def code(a, b):\n        print('adding')\n        return a + b\n",
+			input:    input,
+			want:     []string{"adding\n", "result:  5\n", "verdict: OK\n"},
+		},
+		{
+			name:     "language flag",
+			file:     "solution.txt",
+			solution: "class Solution:\n    def code(a, b):\n        return a * b\n",
+			lang:     execution.Python,
+			input:    input,
+			want:     []string{"result:  6\n", "verdict: OK\n"},
+		},
+		{
+			name:     "runtime error",
+			file:     "solution.py",
+			solution: "class Solution:\n    def code(a, b):\n        raise ValueError('broken')\n",
+			input:    input,
+			want:     []string{"ValueError: broken", "verdict: RuntimeError\n"},
+			wantErr:  "solution finished with RuntimeError",
+		},
+		{
+			name:      "time limit",
+			file:      "solution.py",
+			solution:  "class Solution:\n    def code(a, b):\n        while True:\n            pass\n",
+			input:     input,
+			timeLimit: 200,
+			want:      []string{"verdict: TimeLimitExceeded\n"},
+			wantErr:   "solution finished with TimeLimitExceeded",
+		},
+		{
+			name:     "unknown extension",
+			file:     "solution.txt",
+			solution: "class Solution:\n    pass\n",
+			input:    input,
+			wantErr:  execution.ErrUnknownLanguage.Error(),
+		},
+		{
+			name:     "invalid input",
+			file:     "solution.py",
+			solution: "class Solution:\n    pass\n",
+			input:    writeFile(t, "invalid.txt", "2 => nope\n"),
+			wantErr:  "Invalid input",
+		},
+		{
+			name:     "run flag not allowed",
+			file:     "solution.py",
+			solution: "class Solution:\n    pass\n",
+			input:    input,
+			run:      []string{"-X"},
+			wantErr:  execution.ErrFlagNotAllowed.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runLang, runInput, runReturns, runTimeLimit, runRun = tt.lang, tt.input, runner.DefaultReturns, tt.timeLimit, tt.run
+			defer func() {
+				runLang, runInput, runTimeLimit, runRun = "", "", 0, nil
+			}()
+			var out bytes.Buffer
+			runCmd.SetOut(&out)
+			runCmd.SetErr(&out)
+			defer runCmd.SetOut(nil)
+			defer runCmd.SetErr(nil)
+
+			err := runCmd.RunE(runCmd, []string{writeFile(t, tt.file, tt.solution)})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("run error = %v\n%v", err, out.String())
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("run error = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%v", want, out.String())
+				}
+			}
+		})
+	}
+}
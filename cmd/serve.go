@@ -3,64 +3,134 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/Strovala/crackview/auth"
 	crackviewHttp "github.com/Strovala/crackview/http"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/ratelimit"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 func init() {
 	rootCmd.AddCommand(serveCmdNew)
 }
 
-func makeServer() *http.Server {
-	router := crackviewHttp.NewCrackviewHandler()
+func makeServer(db *store.Store, hub *session.Hub) (*http.Server, error) {
+	problems, err := problem.LoadBank(viper.GetString("problems"))
+	if err != nil {
+		return nil, err
+	}
+	rubric, err := loadRubric()
+	if err != nil {
+		return nil, err
+	}
+	authenticator, err := loadAuth()
+	if err != nil {
+		return nil, err
+	}
+	limits := ratelimit.Config{}
+	if err := viper.UnmarshalKey("rateLimit", &limits); err != nil {
+		return nil, err
+	}
+	router := crackviewHttp.NewCrackviewHandler(crackviewHttp.Options{
+		Problems:       problems,
+		Store:          db,
+		Rubric:         rubric,
+		Hub:            hub,
+		Auth:           authenticator,
+		RateLimit:      limits,
+		AllowedOrigins: viper.GetStringSlice("allowedOrigins"),
+		Logger:         zap.L(),
+	})
 
+	// routes which run code clear write timeout, see NoWriteTimeout
 	return &http.Server{
 		Addr:         fmt.Sprintf(":%v", viper.GetString("port")),
 		Handler:      router,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
+	}, nil
+}
+
+// loadRubric loads scorecard rubric from config, default rubric is used if it is not configured
+func loadRubric() (session.Rubric, error) {
+	rubric := session.DefaultRubric
+	if !viper.IsSet("rubric") {
+		return rubric, nil
+	}
+	if err := viper.UnmarshalKey("rubric", &rubric); err != nil {
+		return rubric, err
+	}
+	if err := rubric.Check(); err != nil {
+		return rubric, fmt.Errorf("invalid rubric config: %v", err)
 	}
+	return rubric, nil
+}
+
+// loadAuth creates authenticator from config, it returns nil when authentication is disabled
+func loadAuth() (*auth.Authenticator, error) {
+	config := auth.Config{}
+	if err := viper.UnmarshalKey("auth", &config); err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return nil, nil
+	}
+	return auth.New(config)
 }
 
 var serveCmdNew = &cobra.Command{
 	Use:   "server",
 	Short: "Start HTTP Server",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// init db etc
+		db, err := store.Open(viper.GetString("store"))
+		if err != nil {
+			return err
+		}
 
-		srv := makeServer()
+		hub := session.NewHub(db)
+		srv, err := makeServer(db, hub)
+		if err != nil {
+			db.Close()
+			return err
+		}
 
 		done := make(chan os.Signal, 1)
 		signal.Notify(done, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+		logger := zap.L()
 		go func() {
 			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Fatalf("listen: %s\n", err)
+				logger.Fatal("unable to listen", zap.Error(err))
 			}
 		}()
-		log.Print("Server Started")
+		logger.Info("server started", zap.String("addr", srv.Addr))
 
 		<-done
-		log.Print("Server Stopped")
+		logger.Info("server stopped")
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer func() {
-			// extra handling here
-			// close db, etc
+			if err := db.Close(); err != nil {
+				logger.Error("unable to close store", zap.Error(err))
+			}
 			cancel()
 		}()
 
 		if err := srv.Shutdown(ctx); err != nil {
-			log.Fatalf("Server Shutdown Failed:%+v", err)
+			logger.Fatal("server shutdown failed", zap.Error(err))
 		}
+		// edits of sessions which are still open are persisted before store is closed
+		hub.Flush()
 		return nil
 	},
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	stressLang       string
+	stressReference  string
+	stressIterations int
+	stressDuration   time.Duration
+	stressWorkers    int
+	stressSeed       int64
+	stressCompile    []string
+	stressRun        []string
+)
+
+func init() {
+	stressCmd.Flags().StringVar(&stressLang, "lang", "", "language of solution (default is taken from file extension)")
+	stressCmd.Flags().StringVar(&stressReference, "reference-lang", "", "language of reference solution (default is language of solution)")
+	stressCmd.Flags().IntVar(&stressIterations, "iterations", problem.DefaultStressIterations, "number of random inputs")
+	stressCmd.Flags().DurationVar(&stressDuration, "duration", 0, "stop after duration even if not every input was run")
+	stressCmd.Flags().IntVar(&stressWorkers, "workers", 0, "number of inputs run at the same time (default is number of CPUs)")
+	stressCmd.Flags().Int64Var(&stressSeed, "seed", 0, "seed of random generator to reproduce run")
+	stressCmd.Flags().StringArrayVar(&stressCompile, "compile-flag", nil, "compiler flag of solution allowed by flags config, can be repeated")
+	stressCmd.Flags().StringArrayVar(&stressRun, "run-flag", nil, "run flag of solution allowed by flags config, can be repeated")
+	rootCmd.AddCommand(stressCmd)
+}
+
+var stressCmd = &cobra.Command{
+	Use:   "stress <problem dir> <solution>",
+	Short: "Compare solution with reference solution on random inputs",
+	Long: `Run solution and reference solution of problem on random inputs generated
+by generator declared in problem.yaml and report smallest input they disagree on.
+Command fails when such input is found.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := problem.Load(args[0])
+		if err != nil {
+			return err
+		}
+		solution, err := ioutil.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		lang := stressLang
+		if lang == "" {
+			if lang, err = execution.LanguageOf(args[1]); err != nil {
+				return err
+			}
+		}
+		result, err := p.Stress(problem.StressOptions{
+			Lang:          lang,
+			Solution:      string(solution),
+			Flags:         requestedFlags(stressCompile, stressRun),
+			ReferenceLang: stressReference,
+			Iterations:    stressIterations,
+			Duration:      stressDuration,
+			Workers:       stressWorkers,
+			Seed:          stressSeed,
+			Logger:        zap.NewNop(),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("seed:       %v\n", result.Seed)
+		fmt.Printf("iterations: %v\n", result.Iterations)
+		fmt.Printf("failures:   %v\n", result.Failures)
+		if result.Failure == nil {
+			return nil
+		}
+		failure := result.Failure
+		fmt.Printf("\nsmallest failing input:\n%v\n\n", failure.Input)
+		fmt.Printf("expected: %v\n", failure.Expected)
+		fmt.Printf("actual:   %v\n", failure.Result.Result.Result)
+		fmt.Printf("verdict:  %v\n", failure.Result.Verdict)
+		if failure.Result.Message != "" {
+			fmt.Printf("message:  %v\n", failure.Result.Message)
+		}
+		if failure.Result.Result.Error != "" {
+			fmt.Printf("error:\n%v\n", failure.Result.Result.Error)
+		}
+		cmd.SilenceUsage = true
+		return errors.New("solution disagrees with reference")
+	},
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var testLangs []string
+
+func init() {
+	testCmd.Flags().StringSliceVar(&testLangs, "lang", nil, "languages of reference solutions to run (default is every reference)")
+	rootCmd.AddCommand(testCmd)
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test <problem dir>",
+	Short: "Validate problem by running its reference solutions on every test",
+	Long: `Run reference solutions from reference/solution.{py,java,cpp} of problem on
+every public and hidden test and report tests where references disagree with
+each other or with expected output. Command fails when any test has issue.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := problem.Load(args[0])
+		if err != nil {
+			return err
+		}
+		langs, err := execution.Languages(testLangs)
+		if err != nil {
+			return err
+		}
+		verification, err := p.Verify(langs, zap.NewNop())
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "TEST\tEXPECTED\t%v\tISSUE\n", strings.ToUpper(strings.Join(verification.Langs, "\t")))
+		for _, c := range verification.Cases {
+			name := c.Test.Name
+			if c.Test.Hidden {
+				name = "hidden/" + name
+			}
+			fmt.Fprintf(w, "%v\t%v", name, c.Test.Expected)
+			for _, lang := range verification.Langs {
+				fmt.Fprintf(w, "\t%v", outcome(c.Results[lang]))
+			}
+			fmt.Fprintf(w, "\t%v\n", c.Issue)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if verification.Failed() {
+			cmd.SilenceUsage = true
+			return errors.New("some tests have issues")
+		}
+		return nil
+	},
+}
+
+// outcome describes result of reference on test in one table cell
+func outcome(result *problem.TestResult) string {
+	switch result.Verdict {
+	case problem.VerdictAccepted:
+		return "ok"
+	case problem.VerdictWrongAnswer:
+		return result.Result.Result
+	}
+	return result.Verdict
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+)
+
+// saveProblem saves problem summing two numbers with python reference and tests
+// with given expected outputs
+func saveProblem(t *testing.T, expected ...string) string {
+	p := &problem.Problem{
+		ID:         "sum",
+		Signature:  problem.Signature{Args: []string{"int", "int"}, Returns: "int"},
+		References: map[string]string{execution.Python: "class Solution:\n    def code(a, b):\n        return a + b\n"},
+	}
+	for i, e := range expected {
+		p.Tests = append(p.Tests, &problem.Test{Name: strconv.Itoa(i + 1), Input: "2 => int\n3 => int", Expected: e, Hidden: i > 0})
+	}
+	dir := filepath.Join(t.TempDir(), p.ID)
+	if err := p.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestTestCommand(t *testing.T) {
+	requirePython(t)
+	tests := []struct {
+		name     string
+		expected []string
+		langs    []string
+		// want are table rows with cells separated by single space
+		want    []string
+		wantErr string
+	}{
+		{
+			name:     "passing",
+			expected: []string{"5", "5"},
+			want:     []string{"TEST EXPECTED PYTHON ISSUE", "1 5 ok", "hidden/2 5 ok"},
+		},
+		{
+			name:     "wrong expected output",
+			expected: []string{"5", "6"},
+			want:     []string{"hidden/2 6 5 " + problem.IssueExpected},
+			wantErr:  "some tests have issues",
+		},
+		{
+			name:     "language without reference",
+			expected: []string{"5"},
+			langs:    []string{"java"},
+			wantErr:  problem.ErrNoReferences.Error(),
+		},
+		{
+			name:     "unknown language",
+			expected: []string{"5"},
+			langs:    []string{"cobol"},
+			wantErr:  execution.ErrUnknownLanguage.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testLangs = tt.langs
+			defer func() { testLangs = nil }()
+			var out bytes.Buffer
+			testCmd.SetOut(&out)
+			defer testCmd.SetOut(nil)
+
+			err := testCmd.RunE(testCmd, []string{saveProblem(t, tt.expected...)})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("test error = %v\n%v", err, out.String())
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("test error = %v, want %v", err, tt.wantErr)
+			}
+			var rows []string
+			for _, line := range strings.Split(out.String(), "\n") {
+				rows = append(rows, strings.Join(strings.Fields(line), " "))
+			}
+			table := strings.Join(rows, "\n")
+			for _, want := range tt.want {
+				if !strings.Contains(table, want) {
+					t.Errorf("output does not contain %q:\n%v", want, out.String())
+				}
+			}
+		})
+	}
+}
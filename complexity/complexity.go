@@ -0,0 +1,161 @@
+package complexity
+
+import (
+	"math"
+)
+
+// Complexity classes runtime is fitted against, ordered from simplest
+const (
+	Constant     = "O(1)"
+	Logarithmic  = "O(log n)"
+	Linear       = "O(n)"
+	Linearithmic = "O(n log n)"
+	Quadratic    = "O(n^2)"
+	Exponential  = "O(2^n)"
+)
+
+// tolerance is how much worse fit of simpler class can be to still be preferred
+const tolerance = 0.02
+
+// noiseFloor is growth of runtime in milliseconds below which it is treated as measurement noise
+const noiseFloor = 10
+
+// minFit is coefficient of determination below which runtime is treated as noise around constant
+const minFit = 0.5
+
+type class struct {
+	name string
+	f    func(n float64) float64
+}
+
+var classes = []class{
+	{Constant, func(n float64) float64 { return 0 }},
+	{Logarithmic, func(n float64) float64 { return math.Log2(n) }},
+	{Linear, func(n float64) float64 { return n }},
+	{Linearithmic, func(n float64) float64 { return n * math.Log2(n) }},
+	{Quadratic, func(n float64) float64 { return n * n }},
+	{Exponential, func(n float64) float64 { return math.Pow(2, n) }},
+}
+
+// Measurement is run time of solution on input of size, RunTime is in
+// milliseconds and covers only call of solution when run succeeds
+type Measurement struct {
+	Size    int    `json:"size"`
+	RunTime int64  `json:"runTime"`
+	Verdict string `json:"verdict"`
+}
+
+// Fit is how well runtime fits class, R2 is coefficient of determination
+// of runtime = a + b * f(size)
+type Fit struct {
+	Class string  `json:"class"`
+	R2    float64 `json:"r2"`
+}
+
+// Estimate is estimated complexity class with fits of every class it was chosen from
+type Estimate struct {
+	Class        string        `json:"class"`
+	Fits         []Fit         `json:"fits"`
+	Measurements []Measurement `json:"measurements"`
+}
+
+// Classify fits measurements against every class and chooses simplest class
+// which fits almost as well as the best one, classes which overflow for
+// measured sizes are skipped. Runtime which barely grows is estimated as constant
+func Classify(measurements []Measurement) *Estimate {
+	estimate := &Estimate{Class: Constant, Measurements: measurements}
+	if len(measurements) < 3 {
+		return estimate
+	}
+	sizes := make([]float64, len(measurements))
+	times := make([]float64, len(measurements))
+	for i, m := range measurements {
+		sizes[i] = float64(m.Size)
+		times[i] = float64(m.RunTime)
+	}
+	if spread(times) < noiseFloor {
+		return estimate
+	}
+
+	best := 0.0
+	for _, c := range classes {
+		r2, ok := fit(sizes, times, c.f)
+		if !ok {
+			continue
+		}
+		estimate.Fits = append(estimate.Fits, Fit{Class: c.name, R2: r2})
+		best = math.Max(best, r2)
+	}
+	if best < minFit {
+		return estimate
+	}
+	for _, f := range estimate.Fits {
+		if f.Class != Constant && f.R2 >= best-tolerance {
+			estimate.Class = f.Class
+			break
+		}
+	}
+	return estimate
+}
+
+// fit fits times = a + b * f(sizes) with least squares and returns coefficient of
+// determination, fits with negative slope or overflowing values are rejected
+func fit(sizes, times []float64, f func(float64) float64) (float64, bool) {
+	n := float64(len(sizes))
+	var sumX, sumY, sumXX, sumXY float64
+	for i := range sizes {
+		x := f(sizes[i])
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return 0, false
+		}
+		sumX += x
+		sumY += times[i]
+		sumXX += x * x
+		sumXY += x * times[i]
+	}
+	meanY := sumY / n
+	var total float64
+	for _, y := range times {
+		total += (y - meanY) * (y - meanY)
+	}
+	if total == 0 {
+		return 1, true
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		// constant model only explains mean
+		return 0, true
+	}
+	b := (n*sumXY - sumX*sumY) / denominator
+	if b < 0 {
+		return 0, true
+	}
+	a := (sumY - b*sumX) / n
+	var residual float64
+	for i := range sizes {
+		d := times[i] - (a + b*f(sizes[i]))
+		residual += d * d
+	}
+	return 1 - residual/total, true
+}
+
+func spread(values []float64) float64 {
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	return max - min
+}
+
+// Sizes returns sizes doubling from min while they are not greater than max
+func Sizes(min, max int) []int {
+	if min < 1 {
+		min = 1
+	}
+	var sizes []int
+	for size := min; size <= max; size *= 2 {
+		sizes = append(sizes, size)
+	}
+	return sizes
+}
@@ -0,0 +1,73 @@
+package complexity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// measure measures runtime of every size with runTime
+func measure(sizes []int, runTime func(n float64) float64) []Measurement {
+	var measurements []Measurement
+	for _, size := range sizes {
+		measurements = append(measurements, Measurement{Size: size, RunTime: int64(runTime(float64(size)))})
+	}
+	return measurements
+}
+
+func TestClassify(t *testing.T) {
+	sizes := Sizes(64, 8192)
+	tests := []struct {
+		name         string
+		measurements []Measurement
+		want         string
+	}{
+		{name: "constant", measurements: measure(sizes, func(n float64) float64 { return 200 }), want: Constant},
+		{name: "noise below floor", measurements: measure(sizes, func(n float64) float64 { return n / 1000 }), want: Constant},
+		{name: "logarithmic", measurements: measure(sizes, func(n float64) float64 { return 50 * math.Log2(n) }), want: Logarithmic},
+		{name: "linear", measurements: measure(sizes, func(n float64) float64 { return 5 + n/10 }), want: Linear},
+		// n log n fits linear within tolerance, simpler class is preferred
+		{name: "linearithmic within tolerance of linear", measurements: measure(sizes, func(n float64) float64 { return n * math.Log2(n) / 10 }), want: Linear},
+		{name: "quadratic", measurements: measure(sizes, func(n float64) float64 { return n * n / 1000 }), want: Quadratic},
+		{name: "exponential", measurements: measure(Sizes(1, 20), func(n float64) float64 { return math.Pow(2, n) / 100 }), want: Exponential},
+		{name: "decreasing", measurements: measure(sizes, func(n float64) float64 { return 1e6 / n }), want: Constant},
+		{name: "too few measurements", measurements: measure([]int{10, 1000}, func(n float64) float64 { return n }), want: Constant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := Classify(tt.measurements)
+			if estimate.Class != tt.want {
+				t.Errorf("Classify = %v, want %v, fits %+v", estimate.Class, tt.want, estimate.Fits)
+			}
+		})
+	}
+}
+
+func TestClassifySkipsOverflowingClasses(t *testing.T) {
+	estimate := Classify(measure(Sizes(512, 4096), func(n float64) float64 { return n }))
+	for _, f := range estimate.Fits {
+		if f.Class == Exponential {
+			t.Errorf("Classify fitted %v for sizes up to 4096", Exponential)
+		}
+	}
+	if estimate.Class != Linear {
+		t.Errorf("Classify = %v, want %v", estimate.Class, Linear)
+	}
+}
+
+func TestSizes(t *testing.T) {
+	tests := []struct {
+		min, max int
+		want     []int
+	}{
+		{min: 1, max: 8, want: []int{1, 2, 4, 8}},
+		{min: 3, max: 20, want: []int{3, 6, 12}},
+		{min: 0, max: 2, want: []int{1, 2}},
+		{min: 5, max: 4, want: nil},
+	}
+	for _, tt := range tests {
+		if got := Sizes(tt.min, tt.max); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Sizes(%v, %v) = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package doctor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/generator"
+	"github.com/Strovala/crackview/runner"
+	"go.uber.org/zap"
+)
+
+// Statuses of checks, only failed checks make host unfit for running solutions
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusWarn = "WARN"
+	StatusSkip = "SKIP"
+)
+
+// sampleInput is input of hello world solutions which return sum of arguments
+const (
+	sampleInput    = "2 => int\n3 => int"
+	sampleExpected = "5"
+)
+
+// loopTimeLimit is time limit solution which never ends has to be stopped after
+const loopTimeLimit = time.Second
+
+type sample struct {
+	hello string
+	loop  string
+}
+
+var samples = map[string]sample{
+	execution.Python: {
+		hello: "class Solution:\n    def code(a, b):\n        return a + b\n",
+		loop:  "class Solution:\n    def code(a, b):\n        while True:\n            pass\n",
+	},
+	execution.Java: {
+		hello: "class Solution {\n    public static int code(int a, int b) {\n        return a + b;\n    }\n}\n",
+		loop:  "class Solution {\n    public static int code(int a, int b) {\n        while (true) {\n        }\n    }\n}\n",
+	},
+	execution.Cpp: {
+		hello: "class Solution {\npublic:\n    static int code(int a, int b) {\n        return a + b;\n    }\n};\n",
+		loop:  "class Solution {\npublic:\n    static int code(int a, int b) {\n        volatile int i = 0;\n        while (true) {\n            i++;\n        }\n    }\n};\n",
+	},
+}
+
+// Check is result of single host check, Lang is empty for checks which are not
+// specific to language
+type Check struct {
+	Lang   string
+	Name   string
+	Status string
+	Detail string
+}
+
+// Run checks host for every language, workdir is directory solutions run in
+func Run(langs []string, workdir string) []*Check {
+	checks := []*Check{checkWorkdir(workdir), checkUser()}
+	for _, lang := range langs {
+		checks = append(checks, checkLanguage(lang)...)
+	}
+	return checks
+}
+
+// Failed reports whether any check failed
+func Failed(checks []*Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkWorkdir(workdir string) *Check {
+	check := &Check{Name: "workdir", Status: StatusPass}
+	dir, err := ioutil.TempDir(workdir, "crackview")
+	if err != nil {
+		check.Status, check.Detail = StatusFail, err.Error()
+		return check
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, execution.OutputFileName), nil, 0644); err != nil {
+		check.Status, check.Detail = StatusFail, err.Error()
+		return check
+	}
+	check.Detail = filepath.Dir(dir) + " is writable"
+	return check
+}
+
+func checkUser() *Check {
+	check := &Check{Name: "user", Status: StatusPass, Detail: fmt.Sprintf("uid %v", os.Getuid())}
+	if os.Getuid() == 0 {
+		check.Status = StatusWarn
+		check.Detail = "solutions run as root"
+	}
+	return check
+}
+
+func checkLanguage(lang string) []*Check {
+	var checks []*Check
+	ready := true
+	tools, err := execution.Toolchain(lang)
+	if err != nil {
+		return []*Check{{Lang: lang, Name: "toolchain", Status: StatusFail, Detail: err.Error()}}
+	}
+	for _, tool := range tools {
+		check := &Check{Lang: lang, Name: "binary " + tool.Command, Status: StatusPass, Detail: tool.Version}
+		if !tool.OK() {
+			check.Status, check.Detail = StatusFail, tool.Error
+			ready = false
+		}
+		checks = append(checks, check)
+	}
+
+	templates := &Check{Lang: lang, Name: "templates", Status: StatusPass, Detail: "all present"}
+	if missing := generator.MissingTemplates(lang); len(missing) > 0 {
+		templates.Status, templates.Detail = StatusFail, "missing "+strings.Join(missing, ", ")
+		ready = false
+	}
+	checks = append(checks, templates)
+
+	if !ready {
+		return append(checks,
+			&Check{Lang: lang, Name: "hello world", Status: StatusSkip, Detail: "toolchain is not ready"},
+			&Check{Lang: lang, Name: "time limit", Status: StatusSkip, Detail: "toolchain is not ready"},
+		)
+	}
+	return append(checks, checkHello(lang), checkTimeLimit(lang))
+}
+
+// checkHello runs solution through generator and executor and checks its result
+func checkHello(lang string) *Check {
+	check := &Check{Lang: lang, Name: "hello world"}
+	result, err := runner.Run(&runner.Request{
+		Lang:     lang,
+		Solution: samples[lang].hello,
+		Input:    sampleInput,
+		Logger:   zap.NewNop(),
+	})
+	switch {
+	case err != nil:
+		check.Status, check.Detail = StatusFail, err.Error()
+	case result.Verdict != execution.VerdictOK:
+		check.Status, check.Detail = StatusFail, result.Verdict+": "+execution.FirstLine(result.Error)
+	case strings.TrimSpace(result.Result) != sampleExpected:
+		check.Status, check.Detail = StatusFail, fmt.Sprintf("expected %v, got %q", sampleExpected, result.Result)
+	default:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("compiled in %vms, ran in %vms", result.CompileTime, result.RunTime)
+	}
+	return check
+}
+
+// checkTimeLimit checks that solution which never ends is stopped
+func checkTimeLimit(lang string) *Check {
+	check := &Check{Lang: lang, Name: "time limit"}
+	result, err := runner.Run(&runner.Request{
+		Lang:      lang,
+		Solution:  samples[lang].loop,
+		Input:     sampleInput,
+		TimeLimit: loopTimeLimit,
+		Logger:    zap.NewNop(),
+	})
+	switch {
+	case err != nil:
+		check.Status, check.Detail = StatusFail, err.Error()
+	case result.Verdict != execution.VerdictTimeLimit:
+		check.Status, check.Detail = StatusFail, "expected "+execution.VerdictTimeLimit+", got "+result.Verdict
+	default:
+		check.Status = StatusPass
+		check.Detail = fmt.Sprintf("stopped after %vms", result.RunTime)
+	}
+	return check
+}
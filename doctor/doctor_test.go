@@ -0,0 +1,116 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+)
+
+// TestMain runs tests from repository root where templates are
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// statuses maps names of checks of language to their statuses
+func statuses(checks []*Check, lang string) map[string]string {
+	result := make(map[string]string)
+	for _, check := range checks {
+		if check.Lang == lang {
+			result[check.Name] = check.Status
+		}
+	}
+	return result
+}
+
+func TestFailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []*Check
+		want   bool
+	}{
+		{name: "no checks", want: false},
+		{name: "passed", checks: []*Check{{Status: StatusPass}, {Status: StatusSkip}}, want: false},
+		{name: "warned", checks: []*Check{{Status: StatusPass}, {Status: StatusWarn}}, want: false},
+		{name: "failed", checks: []*Check{{Status: StatusPass}, {Status: StatusFail}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Failed(tt.checks); got != tt.want {
+				t.Errorf("Failed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWorkdir(t *testing.T) {
+	tests := []struct {
+		name    string
+		workdir string
+		want    string
+	}{
+		{name: "writable", workdir: t.TempDir(), want: StatusPass},
+		{name: "missing", workdir: filepath.Join(t.TempDir(), "missing"), want: StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if check := checkWorkdir(tt.workdir); check.Status != tt.want {
+				t.Errorf("checkWorkdir(%v) = %+v, want %v", tt.workdir, check, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLanguageNotReady(t *testing.T) {
+	// directory without templates and path without tools
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("PATH", t.TempDir())
+
+	checks := Run([]string{execution.Java}, t.TempDir())
+	want := map[string]string{
+		"binary javac": StatusFail,
+		"binary java":  StatusFail,
+		"templates":    StatusFail,
+		"hello world":  StatusSkip,
+		"time limit":   StatusSkip,
+	}
+	got := statuses(checks, execution.Java)
+	for name, status := range want {
+		if got[name] != status {
+			t.Errorf("%v check = %v, want %v", name, got[name], status)
+		}
+	}
+	if !Failed(checks) {
+		t.Error("checks of language without toolchain did not fail")
+	}
+}
+
+func TestRunPython(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not available")
+	}
+	checks := Run([]string{execution.Python}, t.TempDir())
+	for name, status := range statuses(checks, execution.Python) {
+		if status != StatusPass {
+			t.Errorf("%v check = %v, want %v", name, status, StatusPass)
+		}
+	}
+	if len(statuses(checks, execution.Python)) != 4 {
+		t.Errorf("python checks = %v, want binary, templates, hello world and time limit", statuses(checks, execution.Python))
+	}
+	if Failed(checks) {
+		t.Error("checks failed on host with python")
+	}
+}
@@ -2,11 +2,19 @@ package execution
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"go.uber.org/zap"
 )
 
 // Languages constants
@@ -23,46 +31,256 @@ const (
 	CppMainName    = "main.cpp"
 )
 
+// OutputFileName is name of the file generated code writes solution result to
+const OutputFileName = "output.txt"
+
+// TimeFileName is name of the file generated code writes duration of solution
+// call to, in nanoseconds
+const TimeFileName = "time.txt"
+
+// MainNames maps languages to their main file names
+var MainNames = map[string]string{
+	Python: PythonMainName,
+	Java:   JavaMainName,
+	Cpp:    CppMainName,
+}
+
+// ErrUnknownLanguage is returned when requested language is not supported
+var ErrUnknownLanguage = errors.New("Unknown language")
+
+// LanguageOf returns language of source file by its extension
+func LanguageOf(fileName string) (string, error) {
+	ext := filepath.Ext(fileName)
+	for lang, mainName := range MainNames {
+		if ext != "" && filepath.Ext(mainName) == ext {
+			return lang, nil
+		}
+	}
+	return "", ErrUnknownLanguage
+}
+
+// aliases maps names languages can be configured with to languages
+var aliases = map[string]string{
+	"python":  Python,
+	"python3": Python,
+	"java":    Java,
+	"cpp":     Cpp,
+	"c++":     Cpp,
+}
+
+// Languages maps configured language names to languages without repeating
+// them, names can be separated by commas
+func Languages(names []string) ([]string, error) {
+	var langs []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		for _, part := range strings.Split(name, ",") {
+			part = strings.ToLower(strings.TrimSpace(part))
+			if part == "" {
+				continue
+			}
+			lang, ok := aliases[part]
+			if !ok {
+				return nil, fmt.Errorf("%v: %v", ErrUnknownLanguage, part)
+			}
+			if !seen[lang] {
+				seen[lang] = true
+				langs = append(langs, lang)
+			}
+		}
+	}
+	return langs, nil
+}
+
 func hasError(errString string, keyword string) bool {
 	lowerErrString := strings.ToLower(errString)
 	return strings.Contains(lowerErrString, keyword)
 }
 
+// Verdicts of code execution
+const (
+	VerdictOK               = "OK"
+	VerdictCompilationError = "CompilationError"
+	VerdictRuntimeError     = "RuntimeError"
+	VerdictTimeLimit        = "TimeLimitExceeded"
+)
+
+// Output streams
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
 // CodeResult have output and error
 type CodeResult struct {
 	Output string `json:"output"`
 	Error  string `json:"error"`
+	// Result is value returned by solution serialized in parser syntax
+	Result  string `json:"result"`
+	Verdict string `json:"verdict"`
+	// CompileTime and RunTime are in milliseconds
+	CompileTime int64 `json:"compileTime"`
+	RunTime     int64 `json:"runTime"`
+	// SolutionTime is duration of solution call alone in milliseconds, unlike
+	// RunTime it excludes startup of process and building of input arguments.
+	// It is zero when solution did not return
+	SolutionTime int64 `json:"solutionTime"`
+}
+
+// Chunk is a piece of output produced while code is running
+type Chunk struct {
+	Stream string `json:"stream"`
+	Text   string `json:"text"`
+}
+
+// OutputListener receives output chunks as soon as they are produced,
+// it can be called from multiple goroutines
+type OutputListener func(chunk Chunk)
+
+type listenerWriter struct {
+	stream   string
+	listener OutputListener
+}
+
+func (w *listenerWriter) Write(p []byte) (int, error) {
+	w.listener(Chunk{Stream: w.stream, Text: string(p)})
+	return len(p), nil
+}
+
+func milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
 }
 
 // Executor executes code
 type Executor interface {
 	Execute() (*CodeResult, error)
 	HasError(errString string) bool
+	// Listen registers listener that receives output while code is running
+	Listen(listener OutputListener)
+	// SetTimeLimit limits how long compiled code can run, zero means no limit
+	SetTimeLimit(limit time.Duration)
+	// SetLogger sets logger compile and run steps are logged to
+	SetLogger(logger *zap.Logger)
+	// SetCache sets cache compiled code is reused from, nil disables caching
+	SetCache(cache *Cache)
+	// SetFlags sets flags passed to compiler and to program or interpreter running code
+	SetFlags(flags Flags)
+	// SetContext sets context which stops compilation and run of code once it is done
+	SetContext(ctx context.Context)
+}
+
+type commandResult struct {
+	out      bytes.Buffer
+	errOut   bytes.Buffer
+	duration time.Duration
+	err      error
+	timedOut bool
 }
 
 type baseExecutor struct {
+	Dir                string
 	FileName           string
 	CompileCommandName string
 	CompileCommandArgs []string
+	TimeLimit          time.Duration
+	listener           OutputListener
+	logger             *zap.Logger
+	cache              *Cache
+	flags              Flags
+	ctx                context.Context
+	// artifacts returns names of files compilation produced, they are what is cached
+	artifacts func() []string
 }
 
-func newBaseExecutor(commandName, fileName string) *baseExecutor {
+func newBaseExecutor(dir, commandName, fileName string) *baseExecutor {
 	result := &baseExecutor{
+		Dir:                dir,
 		FileName:           fileName,
 		CompileCommandName: commandName,
+		logger:             zap.L(),
+		ctx:                context.Background(),
 	}
 	result.generateCompileCommandArgs()
 	return result
 }
 
-func (e *baseExecutor) runCommand(name string, arg ...string) (bytes.Buffer, bytes.Buffer) {
-	cmd := exec.Command(name, arg...)
-	var out bytes.Buffer
-	var errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-	_ = cmd.Run()
-	return out, errOut
+// Listen registers listener that receives output while code is running
+func (e *baseExecutor) Listen(listener OutputListener) {
+	e.listener = listener
+}
+
+// SetTimeLimit limits how long compiled code can run, zero means no limit
+func (e *baseExecutor) SetTimeLimit(limit time.Duration) {
+	e.TimeLimit = limit
+}
+
+// SetLogger sets logger compile and run steps are logged to
+func (e *baseExecutor) SetLogger(logger *zap.Logger) {
+	e.logger = logger
+}
+
+// SetCache sets cache compiled code is reused from, nil disables caching
+func (e *baseExecutor) SetCache(cache *Cache) {
+	e.cache = cache
+}
+
+// SetFlags sets flags passed to compiler and to program or interpreter running code
+func (e *baseExecutor) SetFlags(flags Flags) {
+	e.flags = flags
+}
+
+// SetContext sets context which stops compilation and run of code once it is done
+func (e *baseExecutor) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// compileArgs returns compile flags followed by compile command arguments
+func (e *baseExecutor) compileArgs() []string {
+	return append(append([]string{}, e.flags.Compile...), e.CompileCommandArgs...)
+}
+
+// runArgs returns run flags followed by arguments
+func (e *baseExecutor) runArgs(arg ...string) []string {
+	return append(append([]string{}, e.flags.Run...), arg...)
+}
+
+// path returns path of file inside executor directory
+func (e *baseExecutor) path(name string) string {
+	return filepath.Join(e.Dir, name)
+}
+
+func (e *baseExecutor) runCommand(timeLimit time.Duration, name string, arg ...string) *commandResult {
+	ctx := e.ctx
+	if timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeLimit)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, name, arg...)
+	cmd.Dir = e.Dir
+	result := &commandResult{}
+	cmd.Stdout = &result.out
+	cmd.Stderr = &result.errOut
+	if e.listener != nil {
+		cmd.Stdout = io.MultiWriter(&result.out, &listenerWriter{stream: Stdout, listener: e.listener})
+		cmd.Stderr = io.MultiWriter(&result.errOut, &listenerWriter{stream: Stderr, listener: e.listener})
+	}
+	start := time.Now()
+	result.err = cmd.Run()
+	result.duration = time.Since(start)
+	result.timedOut = ctx.Err() == context.DeadlineExceeded
+	if execErr, ok := result.err.(*exec.Error); ok {
+		// binary is missing or not runnable, say so instead of leaving output empty
+		fmt.Fprintf(&result.errOut, "toolchain error: %v is not available on server: %v\n", name, execErr.Err)
+	}
+	e.logger.Debug("command finished",
+		zap.String("command", name),
+		zap.Strings("args", arg),
+		zap.Duration("duration", result.duration),
+		zap.Bool("timedOut", result.timedOut),
+		zap.Error(result.err),
+	)
+	return result
 }
 
 func (e *baseExecutor) generateCompileCommandArgs() {
@@ -75,11 +293,83 @@ func (e *baseExecutor) generateCompileCommandArgs() {
 // should you continue because there is no error returned in case of failed compiling
 // but you shouldn't continue
 func (e *baseExecutor) compile() *CodeResult {
-	out, errOut := e.runCommand(e.CompileCommandName, e.CompileCommandArgs...)
+	key := e.cacheKey()
+	if key != "" && e.cache.Get(key, e.Dir) {
+		e.logger.Debug("reused compiled code", zap.String("key", key))
+		return &CodeResult{Verdict: VerdictOK}
+	}
+	compiled := e.runCommand(0, e.CompileCommandName, e.compileArgs()...)
+	// executors treat error on stderr as failed compilation even when compiler succeeds
+	failed := compiled.err != nil || hasError(compiled.errOut.String(), "error")
+	if key != "" && !failed {
+		if err := e.cache.Put(key, e.Dir, e.artifacts()); err != nil {
+			e.logger.Warn("unable to cache compiled code", zap.Error(err))
+		}
+	}
+	verdict := VerdictOK
+	if failed {
+		verdict = VerdictCompilationError
+	}
 	return &CodeResult{
-		Output: out.String(),
-		Error:  errOut.String(),
+		Output:      compiled.out.String(),
+		Error:       compiled.errOut.String(),
+		Verdict:     verdict,
+		CompileTime: milliseconds(compiled.duration),
+	}
+}
+
+// cacheKey returns key of compiled code in cache, it is empty when code is not cached
+func (e *baseExecutor) cacheKey() string {
+	if e.cache == nil || e.artifacts == nil {
+		return ""
+	}
+	source, err := ioutil.ReadFile(e.path(e.FileName))
+	if err != nil {
+		return ""
+	}
+	return e.cache.Key(e.CompileCommandName, e.compileArgs(), source)
+}
+
+// run runs compiled code and fills result with its output
+func (e *baseExecutor) run(result *CodeResult, name string, arg ...string) {
+	e.finish(result, e.runCommand(e.TimeLimit, name, arg...))
+}
+
+// finish fills result with output of code run and value it returned
+func (e *baseExecutor) finish(result *CodeResult, ran *commandResult) {
+	result.Output = ran.out.String()
+	result.Error = ran.errOut.String()
+	result.RunTime = milliseconds(ran.duration)
+	result.Verdict = VerdictOK
+	if ran.err != nil {
+		result.Verdict = VerdictRuntimeError
+	}
+	if ran.timedOut {
+		result.Verdict = VerdictTimeLimit
+	}
+	if data, err := ioutil.ReadFile(e.path(OutputFileName)); err == nil {
+		result.Result = string(data)
+		_ = os.Remove(e.path(OutputFileName))
+	}
+	if data, err := ioutil.ReadFile(e.path(TimeFileName)); err == nil {
+		if nanoseconds, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			result.SolutionTime = milliseconds(time.Duration(nanoseconds))
+		}
+		_ = os.Remove(e.path(TimeFileName))
+	}
+}
+
+// NewExecutor initializes executor for given language which runs code in dir
+func NewExecutor(lang, dir string) (Executor, error) {
+	switch lang {
+	case Python:
+		return NewPythonExecutor(dir), nil
+	case Java:
+		return NewJavaExecutor(dir), nil
+	case Cpp:
+		return NewCppExecutor(dir), nil
 	}
+	return nil, ErrUnknownLanguage
 }
 
 // PythonExecutor is Executor for python code
@@ -88,9 +378,9 @@ type PythonExecutor struct {
 }
 
 // NewPythonExecutor initializes new instance of PythonExecutor
-func NewPythonExecutor() *PythonExecutor {
+func NewPythonExecutor(dir string) *PythonExecutor {
 	return &PythonExecutor{
-		baseExecutor: newBaseExecutor("python3", PythonMainName),
+		baseExecutor: newBaseExecutor(dir, "python3", PythonMainName),
 	}
 }
 
@@ -101,14 +391,13 @@ func (e *PythonExecutor) HasError(errString string) bool {
 
 // Execute executes code
 func (e *PythonExecutor) Execute() (*CodeResult, error) {
-	result := e.compile()
-	if e.HasError(result.Error) {
-		if err := os.Remove(e.FileName); err != nil {
-			return nil, err
-		}
-		return result, nil
+	// python is interpreted so there is no separate compile step
+	result := &CodeResult{}
+	e.run(result, e.CompileCommandName, e.runArgs(e.CompileCommandArgs...)...)
+	if e.HasError(result.Error) && result.Verdict == VerdictOK {
+		result.Verdict = VerdictRuntimeError
 	}
-	if err := os.Remove(e.FileName); err != nil {
+	if err := os.Remove(e.path(e.FileName)); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -122,13 +411,14 @@ type CppExecutor struct {
 }
 
 // NewCppExecutor initializes new instance of CppExecutor
-func NewCppExecutor() *CppExecutor {
+func NewCppExecutor(dir string) *CppExecutor {
 	result := &CppExecutor{
-		baseExecutor:   newBaseExecutor("c++", CppMainName),
+		baseExecutor:   newBaseExecutor(dir, "c++", CppMainName),
 		ExecutableName: "main",
 	}
 	result.generateCompileCommandArgs()
 	result.generateRunCommandName()
+	result.artifacts = func() []string { return []string{result.ExecutableName} }
 	return result
 }
 
@@ -153,22 +443,19 @@ func (e *CppExecutor) HasError(errString string) bool {
 func (e *CppExecutor) Execute() (*CodeResult, error) {
 	result := e.compile()
 	if e.HasError(result.Error) {
-		if err := os.Remove(e.FileName); err != nil {
+		if err := os.Remove(e.path(e.FileName)); err != nil {
 			return nil, err
 		}
 		return result, nil
 	}
-	out, errOut := e.runCommand(e.RunCommandName)
-	if err := os.Remove(e.FileName); err != nil {
+	e.run(result, e.RunCommandName, e.runArgs()...)
+	if err := os.Remove(e.path(e.FileName)); err != nil {
 		return nil, err
 	}
-	if err := os.Remove(e.ExecutableName); err != nil {
+	if err := os.Remove(e.path(e.ExecutableName)); err != nil {
 		return nil, err
 	}
-	return &CodeResult{
-		Output: out.String(),
-		Error:  errOut.String(),
-	}, nil
+	return result, nil
 }
 
 // JavaExecutor is Executor for java code
@@ -179,16 +466,18 @@ type JavaExecutor struct {
 }
 
 // NewJavaExecutor initializes new instance of JavaExecutor
-func NewJavaExecutor() *JavaExecutor {
-	return &JavaExecutor{
-		baseExecutor:   newBaseExecutor("javac", JavaMainName),
+func NewJavaExecutor(dir string) *JavaExecutor {
+	result := &JavaExecutor{
+		baseExecutor:   newBaseExecutor(dir, "javac", JavaMainName),
 		RunCommandName: "java",
 		RunCommandArgs: []string{"Main"},
 	}
+	result.artifacts = result.classFiles
+	return result
 }
 
 func (e *JavaExecutor) classFiles() []string {
-	files, _ := ioutil.ReadDir("./")
+	files, _ := ioutil.ReadDir(e.path("."))
 	var fileNames []string
 	for _, file := range files {
 		if strings.Contains(file.Name(), ".class") {
@@ -201,7 +490,7 @@ func (e *JavaExecutor) classFiles() []string {
 func (e *JavaExecutor) removeClassFiles() error {
 	classNames := e.classFiles()
 	for _, className := range classNames {
-		if err := os.Remove(className); err != nil {
+		if err := os.Remove(e.path(className)); err != nil {
 			return err
 		}
 	}
@@ -220,20 +509,17 @@ func (e *JavaExecutor) Execute() (*CodeResult, error) {
 		if err := e.removeClassFiles(); err != nil {
 			return nil, err
 		}
-		if err := os.Remove(e.FileName); err != nil {
+		if err := os.Remove(e.path(e.FileName)); err != nil {
 			return nil, err
 		}
 		return result, nil
 	}
-	out, errOut := e.runCommand(e.RunCommandName, e.RunCommandArgs...)
+	e.run(result, e.RunCommandName, e.runArgs(e.RunCommandArgs...)...)
 	if err := e.removeClassFiles(); err != nil {
 		return nil, err
 	}
-	if err := os.Remove(e.FileName); err != nil {
+	if err := os.Remove(e.path(e.FileName)); err != nil {
 		return nil, err
 	}
-	return &CodeResult{
-		Output: out.String(),
-		Error:  errOut.String(),
-	}, nil
+	return result, nil
 }
@@ -0,0 +1,39 @@
+package execution
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLanguages(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "none", names: nil, want: nil},
+		{name: "comma separated", names: []string{"python,java,c++"}, want: []string{Python, Java, Cpp}},
+		{name: "aliases are not repeated", names: []string{"Python3", " python ", "cpp,c++"}, want: []string{Python, Cpp}},
+		{name: "empty parts", names: []string{",java,,"}, want: []string{Java}},
+		{name: "unknown", names: []string{"java,cobol"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Languages(tt.names)
+			if tt.wantErr {
+				if err == nil || !strings.HasPrefix(err.Error(), ErrUnknownLanguage.Error()) {
+					t.Fatalf("Languages(%v) error = %v, want %v", tt.names, err, ErrUnknownLanguage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Languages(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionTTL is how long compiler version is reused before it is read again,
+// toolchain upgraded while server runs stops matching cached artifacts after it
+const versionTTL = time.Minute
+
+// Cache stores compiled artifacts on disk by key made of source, compiler
+// version and flags. Least recently used entries are evicted once artifacts
+// take more than max bytes
+type Cache struct {
+	dir string
+	max int64
+
+	mu       sync.Mutex
+	versions map[string]compilerVersion
+	now      func() time.Time
+	// entries keeps entries from being evicted while they are copied
+	entries sync.RWMutex
+}
+
+type compilerVersion struct {
+	version   string
+	checkedAt time.Time
+}
+
+// NewCache creates cache in dir which keeps at most max bytes of artifacts,
+// max of zero means no limit
+func NewCache(dir string, max int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir, max: max, versions: make(map[string]compilerVersion), now: time.Now}, nil
+}
+
+// Key returns key of artifacts compiled from source with compiler and its arguments
+func (c *Cache) Key(compiler string, args []string, source []byte) string {
+	hash := sha256.New()
+	for _, part := range append([]string{compiler, c.version(compiler)}, args...) {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(source)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// version returns path and version of compiler, it is read again once it is
+// older than versionTTL
+func (c *Cache) version(compiler string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	cached, ok := c.versions[compiler]
+	if !ok || now.Sub(cached.checkedAt) >= versionTTL {
+		tool := checkTool(compiler)
+		cached = compilerVersion{version: tool.Path + " " + tool.Version, checkedAt: now}
+		c.versions[compiler] = cached
+	}
+	return cached.version
+}
+
+// Get copies artifacts stored under key to dir, it reports whether they were
+// found. Artifacts copied before copy fails are removed from dir
+func (c *Cache) Get(key, dir string) bool {
+	c.entries.RLock()
+	defer c.entries.RUnlock()
+	entry := filepath.Join(c.dir, key)
+	files, err := ioutil.ReadDir(entry)
+	if err != nil {
+		return false
+	}
+	for i, file := range files {
+		if err := copyFile(filepath.Join(entry, file.Name()), filepath.Join(dir, file.Name())); err != nil {
+			for _, copied := range files[:i] {
+				_ = os.Remove(filepath.Join(dir, copied.Name()))
+			}
+			return false
+		}
+	}
+	// modification time of entry orders entries for eviction
+	now := time.Now()
+	_ = os.Chtimes(entry, now, now)
+	return true
+}
+
+// Put stores files from dir under key and evicts least recently used entries
+func (c *Cache) Put(key, dir string, files []string) error {
+	// entry is prepared aside so concurrent Get never sees it partially written
+	tmp, err := ioutil.TempDir(c.dir, ".put")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	for _, name := range files {
+		if err := copyFile(filepath.Join(dir, name), filepath.Join(tmp, name)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, filepath.Join(c.dir, key)); err != nil {
+		// entry stored by concurrent run is as good as this one
+		if _, statErr := os.Stat(filepath.Join(c.dir, key)); statErr != nil {
+			return err
+		}
+	}
+	return c.evict()
+}
+
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes least recently used entries until cache fits its limit
+func (c *Cache) evict() error {
+	if c.max <= 0 {
+		return nil
+	}
+	c.entries.Lock()
+	defer c.entries.Unlock()
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var entries []cacheEntry
+	var total int64
+	for _, info := range infos {
+		if !info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		entry := cacheEntry{path: filepath.Join(c.dir, info.Name()), modTime: info.ModTime()}
+		files, err := ioutil.ReadDir(entry.path)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			entry.size += file.Size()
+		}
+		total += entry.size
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	for _, entry := range entries {
+		if total <= c.max {
+			break
+		}
+		if err := os.RemoveAll(entry.path); err != nil {
+			return err
+		}
+		total -= entry.size
+	}
+	return nil
+}
+
+// copyFile copies file keeping its permissions so executables stay executable,
+// partially written destination is removed
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
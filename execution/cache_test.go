@@ -0,0 +1,168 @@
+package execution
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeFiles writes files with content to new temporary directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func newTestCache(t *testing.T, max int64) *Cache {
+	c, err := NewCache(t.TempDir(), max)
+	if err != nil {
+		t.Fatalf("NewCache error: %v", err)
+	}
+	return c
+}
+
+func TestCacheGetPut(t *testing.T) {
+	c := newTestCache(t, 0)
+	src := writeFiles(t, map[string]string{"main": "binary", "main.cpp": "source"})
+	if err := c.Put("k", src, []string{"main"}); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	dst := t.TempDir()
+	if !c.Get("k", dst) {
+		t.Fatal("Get of stored key missed")
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dst, "main"))
+	if err != nil || string(data) != "binary" {
+		t.Fatalf("cached artifact = %q, %v, want %q", data, err, "binary")
+	}
+	if info, err := os.Stat(filepath.Join(dst, "main")); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("cached artifact mode = %v, %v, want executable", info.Mode(), err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "main.cpp")); !os.IsNotExist(err) {
+		t.Errorf("file which is not artifact was cached")
+	}
+	if c.Get("other", t.TempDir()) {
+		t.Error("Get of missing key hit")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newTestCache(t, 12)
+	src := writeFiles(t, map[string]string{"main": "123456"})
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a", "b"} {
+		if err := c.Put(key, src, []string{"main"}); err != nil {
+			t.Fatalf("Put error: %v", err)
+		}
+		at := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(filepath.Join(c.dir, key), at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// use makes oldest entry most recently used
+	if !c.Get("a", t.TempDir()) {
+		t.Fatal("Get of stored key missed")
+	}
+	if err := c.Put("c", src, []string{"main"}); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if got := c.Get(key, t.TempDir()); got != want {
+			t.Errorf("Get(%v) after eviction = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestCacheGetRemovesPartialCopy(t *testing.T) {
+	c := newTestCache(t, 0)
+	src := writeFiles(t, map[string]string{"a.class": "a", "b.class": "b"})
+	if err := c.Put("k", src, []string{"a.class", "b.class"}); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+	dst := t.TempDir()
+	// directory in place of second artifact makes its copy fail
+	if err := os.Mkdir(filepath.Join(dst, "b.class"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get("k", dst) {
+		t.Fatal("Get with failed copy hit")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "a.class")); !os.IsNotExist(err) {
+		t.Error("artifact copied before failure was left in dir")
+	}
+}
+
+func TestCacheConcurrentGetPut(t *testing.T) {
+	c := newTestCache(t, 64)
+	content := bytes.Repeat([]byte("x"), 16)
+	src := writeFiles(t, map[string]string{"main": string(content)})
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				key := fmt.Sprint((i + j) % 6)
+				if err := c.Put(key, src, []string{"main"}); err != nil {
+					errs <- err
+					return
+				}
+				dst, err := ioutil.TempDir("", "crackview")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if c.Get(key, dst) {
+					if data, err := ioutil.ReadFile(filepath.Join(dst, "main")); err != nil || !bytes.Equal(data, content) {
+						errs <- fmt.Errorf("Get(%v) copied %q, %v", key, data, err)
+					}
+				}
+				os.RemoveAll(dst)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestCacheKeyReadsVersionAgain(t *testing.T) {
+	bin := t.TempDir()
+	version := filepath.Join(bin, "version")
+	compiler := "#!/bin/sh\ncat " + version + "\n"
+	if err := ioutil.WriteFile(filepath.Join(bin, "fakecc"), []byte(compiler), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(version, []byte("fakecc 1.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := newTestCache(t, 0)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	key := c.Key("fakecc", nil, []byte("source"))
+	if err := ioutil.WriteFile(version, []byte("fakecc 2.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(versionTTL / 2)
+	if got := c.Key("fakecc", nil, []byte("source")); got != key {
+		t.Error("version was read again before TTL passed")
+	}
+	now = now.Add(versionTTL)
+	if got := c.Key("fakecc", nil, []byte("source")); got == key {
+		t.Error("key did not change after compiler was upgraded")
+	}
+}
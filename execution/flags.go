@@ -0,0 +1,59 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFlagNotAllowed is returned when requested flag is not in allowed list of language
+var ErrFlagNotAllowed = errors.New("Flag is not allowed")
+
+// Flags are options passed to compiler and to program or interpreter which runs code
+type Flags struct {
+	Compile []string `json:"compile,omitempty" mapstructure:"compile"`
+	Run     []string `json:"run,omitempty" mapstructure:"run"`
+}
+
+// LanguageFlags are flags language is always compiled and run with and flags
+// requests can add to them
+type LanguageFlags struct {
+	Flags   `mapstructure:",squash"`
+	Allowed Flags `json:"allowed" mapstructure:"allowed"`
+}
+
+// Select returns default flags followed by requested ones so requested flags
+// override defaults, every requested flag has to be allowed
+func (l *LanguageFlags) Select(requested *Flags) (Flags, error) {
+	selected := Flags{
+		Compile: append([]string{}, l.Compile...),
+		Run:     append([]string{}, l.Run...),
+	}
+	if requested == nil {
+		return selected, nil
+	}
+	if err := allowed(requested.Compile, l.Allowed.Compile); err != nil {
+		return Flags{}, err
+	}
+	if err := allowed(requested.Run, l.Allowed.Run); err != nil {
+		return Flags{}, err
+	}
+	selected.Compile = append(selected.Compile, requested.Compile...)
+	selected.Run = append(selected.Run, requested.Run...)
+	return selected, nil
+}
+
+func allowed(flags, allowed []string) error {
+	for _, flag := range flags {
+		found := false
+		for _, candidate := range allowed {
+			if flag == candidate {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%v: %v", ErrFlagNotAllowed, flag)
+		}
+	}
+	return nil
+}
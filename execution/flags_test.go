@@ -0,0 +1,68 @@
+package execution
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	language := &LanguageFlags{
+		Flags:   Flags{Compile: []string{"-O2"}, Run: []string{"-Xss64m"}},
+		Allowed: Flags{Compile: []string{"-O0", "-g"}, Run: []string{"-ea"}},
+	}
+	tests := []struct {
+		name      string
+		requested *Flags
+		want      Flags
+		wantErr   bool
+	}{
+		{name: "defaults", want: Flags{Compile: []string{"-O2"}, Run: []string{"-Xss64m"}}},
+		{name: "empty request", requested: &Flags{}, want: Flags{Compile: []string{"-O2"}, Run: []string{"-Xss64m"}}},
+		{
+			name:      "requested follow defaults",
+			requested: &Flags{Compile: []string{"-O0", "-g"}, Run: []string{"-ea"}},
+			want:      Flags{Compile: []string{"-O2", "-O0", "-g"}, Run: []string{"-Xss64m", "-ea"}},
+		},
+		{name: "compile flag not allowed", requested: &Flags{Compile: []string{"-fplugin=x"}}, wantErr: true},
+		{name: "run flag allowed only for compile", requested: &Flags{Run: []string{"-g"}}, wantErr: true},
+		{name: "default flag is not requestable", requested: &Flags{Compile: []string{"-O2"}}, wantErr: true},
+		{name: "flag with extra text", requested: &Flags{Run: []string{"-ea "}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := language.Select(tt.requested)
+			if tt.wantErr {
+				if err == nil || !strings.HasPrefix(err.Error(), ErrFlagNotAllowed.Error()) {
+					t.Fatalf("Select error = %v, want %v", err, ErrFlagNotAllowed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectDoesNotModifyDefaults(t *testing.T) {
+	language := &LanguageFlags{
+		Flags:   Flags{Compile: make([]string, 1, 4)},
+		Allowed: Flags{Compile: []string{"-g"}},
+	}
+	language.Compile[0] = "-O2"
+	first, err := language.Select(&Flags{Compile: []string{"-g"}})
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	second, err := language.Select(nil)
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if !reflect.DeepEqual(language.Compile, []string{"-O2"}) || !reflect.DeepEqual(second.Compile, []string{"-O2"}) {
+		t.Errorf("defaults changed to %v after selecting %v", second.Compile, first.Compile)
+	}
+}
@@ -0,0 +1,366 @@
+package execution
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// workerClass is name of class warm JVM processes run
+const workerClass = "Worker"
+
+// workerSource connects to port given as first argument and sends token given
+// as second one. Then it reads directory of compiled Main class per line, runs
+// it in its own class loader with output captured and replies with exit status,
+// lengths of stdout and stderr, whether threads started by code are still
+// running and bytes of stdout and stderr. Protocol does not use standard
+// streams of process so code writing to them directly can not break it
+const workerSource = `import java.io.*;
+import java.lang.reflect.*;
+import java.net.*;
+import java.nio.charset.StandardCharsets;
+import java.util.*;
+
+public class Worker {
+    public static void main(String[] args) throws Exception {
+        Socket socket = new Socket(InetAddress.getLoopbackAddress(), Integer.parseInt(args[0]));
+        BufferedReader in = new BufferedReader(new InputStreamReader(socket.getInputStream(), StandardCharsets.UTF_8));
+        OutputStream protocol = new BufferedOutputStream(socket.getOutputStream());
+        protocol.write((args[1] + "\n").getBytes(StandardCharsets.UTF_8));
+        protocol.flush();
+        String dir;
+        while ((dir = in.readLine()) != null) {
+            Set<Thread> before = new HashSet<>(Thread.getAllStackTraces().keySet());
+            ByteArrayOutputStream out = new ByteArrayOutputStream();
+            ByteArrayOutputStream err = new ByteArrayOutputStream();
+            System.setIn(new ByteArrayInputStream(new byte[0]));
+            System.setOut(new PrintStream(out, true, "UTF-8"));
+            System.setErr(new PrintStream(err, true, "UTF-8"));
+            System.setProperty("crackview.dir", dir);
+            int status = 0;
+            // parent skips application class path so classes of previous runs and worker are not visible
+            ClassLoader parent = ClassLoader.getSystemClassLoader().getParent();
+            try (URLClassLoader loader = new URLClassLoader(new URL[]{new File(dir).toURI().toURL()}, parent)) {
+                Method main = loader.loadClass("Main").getDeclaredMethod("main", String[].class);
+                main.setAccessible(true);
+                main.invoke(null, (Object) new String[0]);
+            } catch (InvocationTargetException e) {
+                e.getCause().printStackTrace();
+                status = 1;
+            } catch (Throwable e) {
+                e.printStackTrace();
+                status = 1;
+            }
+            System.out.flush();
+            System.err.flush();
+            // threads code left running could affect next runs
+            int lingering = 0;
+            for (Thread thread : Thread.getAllStackTraces().keySet()) {
+                if (thread.isAlive() && !thread.isDaemon() && !before.contains(thread)) {
+                    lingering = 1;
+                }
+            }
+            byte[] stdout = out.toByteArray();
+            byte[] stderr = err.toByteArray();
+            protocol.write((status + " " + stdout.length + " " + stderr.length + " " + lingering + "\n").getBytes(StandardCharsets.UTF_8));
+            protocol.write(stdout);
+            protocol.write(stderr);
+            protocol.flush();
+        }
+    }
+}
+`
+
+// workerStartTimeout limits how long JVM process can take to connect to pool
+const workerStartTimeout = 30 * time.Second
+
+// Errors of JVM pool
+var (
+	ErrNoWorker   = errors.New("Unable to start JVM worker")
+	ErrPoolClosed = errors.New("JVM pool is closed")
+)
+
+// JVMPool keeps warm JVM processes which run compiled Java code without paying
+// JVM startup on every run. Process is replaced after maxRuns runs, when code
+// exceeds time limit or when it crashes
+type JVMPool struct {
+	dir     string
+	maxRuns int
+	// flags are run flags JVM processes are started with
+	flags []string
+	// slots hold idle workers, nil slot is worker which is not started yet
+	slots     chan *jvmWorker
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewJVMPool compiles worker in dir and creates pool of at most size processes,
+// processes are started with run flags when they are first needed
+func NewJVMPool(dir string, size, maxRuns int, flags []string) (*JVMPool, error) {
+	if size <= 0 {
+		return nil, errors.New("JVM pool size has to be positive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, workerClass+".java"), []byte(workerSource), 0644); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("javac", "-d", dir, filepath.Join(dir, workerClass+".java")).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v: %s", ErrNoWorker, err, out)
+	}
+	p := &JVMPool{dir: dir, maxRuns: maxRuns, flags: flags, slots: make(chan *jvmWorker, size), closed: make(chan struct{})}
+	for i := 0; i < size; i++ {
+		p.slots <- nil
+	}
+	return p, nil
+}
+
+// Close stops every worker, it waits for workers which are running code.
+// Runs which start after pool is closed return ErrPoolClosed
+func (p *JVMPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		for i := 0; i < cap(p.slots); i++ {
+			if w := <-p.slots; w != nil {
+				w.stop()
+			}
+		}
+	})
+}
+
+// acquire takes idle worker, it fails once pool is closed
+func (p *JVMPool) acquire() (*jvmWorker, error) {
+	select {
+	case <-p.closed:
+		return nil, ErrPoolClosed
+	case w := <-p.slots:
+		select {
+		case <-p.closed:
+			// slot is returned so Close can stop its worker
+			p.slots <- w
+			return nil, ErrPoolClosed
+		default:
+			return w, nil
+		}
+	}
+}
+
+// run runs compiled Main class in dir on idle worker, it returns ErrPoolClosed
+// without running code when pool is closed
+func (p *JVMPool) run(dir string, timeLimit time.Duration, logger *zap.Logger) (*commandResult, error) {
+	w, err := p.acquire()
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		if w, err = p.start(); err != nil {
+			p.slots <- nil
+			result := &commandResult{err: err}
+			fmt.Fprintf(&result.errOut, "%v: %v\n", ErrNoWorker, err)
+			return result, nil
+		}
+	}
+	result, healthy := w.run(dir, timeLimit)
+	if healthy && (p.maxRuns <= 0 || w.runs < p.maxRuns) {
+		p.slots <- w
+	} else {
+		w.stop()
+		p.slots <- nil
+	}
+	logger.Debug("jvm worker finished",
+		zap.Int("runs", w.runs),
+		zap.Bool("recycled", !healthy || (p.maxRuns > 0 && w.runs >= p.maxRuns)),
+		zap.Duration("duration", result.duration),
+		zap.Bool("timedOut", result.timedOut),
+		zap.Error(result.err),
+	)
+	return result, nil
+}
+
+// start starts JVM process and waits for it to connect, process is given random
+// token so no other process can pose as it
+func (p *JVMPool) start() (*jvmWorker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	token := randomToken()
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	cmd := exec.Command("java", append(append([]string{}, p.flags...), "-cp", p.dir, workerClass, port, token)...)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	w := &jvmWorker{cmd: cmd, exited: make(chan struct{})}
+	go func() {
+		_ = cmd.Wait()
+		close(w.exited)
+		// process which exits before it connects is not waited for
+		listener.Close()
+	}()
+	if err := listener.(*net.TCPListener).SetDeadline(time.Now().Add(workerStartTimeout)); err != nil {
+		w.stop()
+		return nil, err
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			w.stop()
+			return nil, err
+		}
+		reader := bufio.NewReader(conn)
+		_ = conn.SetReadDeadline(time.Now().Add(workerStartTimeout))
+		line, err := reader.ReadString('\n')
+		if err != nil || strings.TrimSuffix(line, "\n") != token {
+			conn.Close()
+			continue
+		}
+		_ = conn.SetReadDeadline(time.Time{})
+		w.conn, w.reader = conn, reader
+		return w, nil
+	}
+}
+
+// randomToken returns random hex string
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type jvmWorker struct {
+	cmd    *exec.Cmd
+	exited chan struct{}
+	conn   net.Conn
+	reader *bufio.Reader
+	runs   int
+}
+
+// run runs code in dir, worker is not healthy when it crashed, was killed
+// because code exceeded time limit or code left its threads running
+func (w *jvmWorker) run(dir string, timeLimit time.Duration) (*commandResult, bool) {
+	type reply struct {
+		status      int
+		lingering   int
+		out, errOut []byte
+		err         error
+	}
+	done := make(chan reply, 1)
+	start := time.Now()
+	go func() {
+		var r reply
+		defer func() { done <- r }()
+		if _, r.err = fmt.Fprintln(w.conn, dir); r.err != nil {
+			return
+		}
+		var outLen, errLen int
+		if _, r.err = fmt.Fscanf(w.reader, "%d %d %d %d\n", &r.status, &outLen, &errLen, &r.lingering); r.err != nil {
+			return
+		}
+		r.out, r.errOut = make([]byte, outLen), make([]byte, errLen)
+		if _, r.err = io.ReadFull(w.reader, r.out); r.err != nil {
+			return
+		}
+		_, r.err = io.ReadFull(w.reader, r.errOut)
+	}()
+
+	var timeout <-chan time.Time
+	if timeLimit > 0 {
+		timer := time.NewTimer(timeLimit)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	result := &commandResult{}
+	select {
+	case r := <-done:
+		result.duration = time.Since(start)
+		if r.err != nil {
+			result.err = r.err
+			fmt.Fprintf(&result.errOut, "JVM worker crashed: %v\n", r.err)
+			return result, false
+		}
+		w.runs++
+		result.out.Write(r.out)
+		result.errOut.Write(r.errOut)
+		if r.status != 0 {
+			result.err = fmt.Errorf("exit status %v", r.status)
+		}
+		return result, r.lingering == 0
+	case <-timeout:
+		result.duration = time.Since(start)
+		result.timedOut = true
+		result.err = context.DeadlineExceeded
+		return result, false
+	}
+}
+
+// stop kills worker, code it is running can not be interrupted otherwise
+func (w *jvmWorker) stop() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+	}
+	_ = w.cmd.Process.Kill()
+	<-w.exited
+}
+
+// PooledJavaExecutor is Executor for java code which runs compiled code on
+// warm JVM of pool. Output is passed to listener only once code finishes so
+// runs which stream output should use JavaExecutor. Run flags are the ones
+// pool was created with, flags set on executor apply only to compiler.
+// Code runs on new JVM once pool is closed
+type PooledJavaExecutor struct {
+	*JavaExecutor
+	pool *JVMPool
+}
+
+// NewPooledJavaExecutor initializes new instance of PooledJavaExecutor
+func NewPooledJavaExecutor(dir string, pool *JVMPool) *PooledJavaExecutor {
+	return &PooledJavaExecutor{JavaExecutor: NewJavaExecutor(dir), pool: pool}
+}
+
+// Execute executes code
+func (e *PooledJavaExecutor) Execute() (*CodeResult, error) {
+	result := e.compile()
+	if !e.HasError(result.Error) {
+		dir, err := filepath.Abs(e.Dir)
+		if err != nil {
+			return nil, err
+		}
+		ran, err := e.pool.run(dir, e.TimeLimit, e.logger)
+		if err == ErrPoolClosed {
+			e.run(result, e.RunCommandName, e.runArgs(e.RunCommandArgs...)...)
+		} else {
+			if e.listener != nil && ran.out.Len() > 0 {
+				e.listener(Chunk{Stream: Stdout, Text: ran.out.String()})
+			}
+			if e.listener != nil && ran.errOut.Len() > 0 {
+				e.listener(Chunk{Stream: Stderr, Text: ran.errOut.String()})
+			}
+			e.finish(result, ran)
+		}
+	}
+	if err := e.removeClassFiles(); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(e.path(e.FileName)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
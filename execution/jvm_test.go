@@ -0,0 +1,185 @@
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeJava speaks worker protocol without JVM, it reports its pid as output
+// and behaves by markers in source. Started with Main it runs code once
+const fakeJava = `#!/usr/bin/env python3
+import os, socket, sys, time
+
+def run(d):
+    src = open(os.path.join(d, "main.java")).read()
+    if "LOOP" in src:
+        time.sleep(60)
+    if "CRASH" in src:
+        sys.exit(3)
+    open(os.path.join(d, "output.txt"), "w").write("[0, 1]")
+    return 1 if "LINGER" in src else 0
+
+if sys.argv[-1] == "Main":
+    run(os.getcwd())
+    print("fresh")
+    sys.exit(0)
+port, token = int(sys.argv[-2]), sys.argv[-1]
+conn = socket.create_connection(("127.0.0.1", port)).makefile("rwb")
+conn.write((token + "\n").encode())
+conn.flush()
+for line in conn:
+    lingering = run(line.decode().strip())
+    # writes to real stdout must not reach protocol
+    print("garbage 1 2 3", flush=True)
+    out = ("%d\n" % os.getpid()).encode()
+    conn.write(b"0 %d 0 %d\n" % (len(out), lingering) + out)
+    conn.flush()
+`
+
+// fakeJavac creates class file of Main in output directory
+const fakeJavac = `#!/bin/sh
+for arg in "$@"; do case "$arg" in *.java) file="$arg";; esac; done
+dir=$(dirname "$file")
+[ "$1" = "-d" ] && dir="$2"
+touch "$dir/Main.class"
+`
+
+// newFakePool creates pool of fake JVMs which is closed with test
+func newFakePool(t *testing.T, size, maxRuns int) *JVMPool {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not available")
+	}
+	bin := t.TempDir()
+	for name, source := range map[string]string{"java": fakeJava, "javac": fakeJavac} {
+		if err := ioutil.WriteFile(filepath.Join(bin, name), []byte(source), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	pool, err := NewJVMPool(t.TempDir(), size, maxRuns, nil)
+	if err != nil {
+		t.Fatalf("NewJVMPool error: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// runPooled runs source on pool and returns result with output
+func runPooled(t *testing.T, pool *JVMPool, source string, timeLimit time.Duration) *CodeResult {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, JavaMainName), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewPooledJavaExecutor(dir, pool)
+	executor.SetTimeLimit(timeLimit)
+	result, err := executor.Execute()
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	return result
+}
+
+func TestJVMPoolReusesWorker(t *testing.T) {
+	pool := newFakePool(t, 1, 0)
+	first := runPooled(t, pool, "class Main {}", 0)
+	if first.Verdict != VerdictOK || first.Result != "[0, 1]" {
+		t.Fatalf("result = %+v, want OK with [0, 1]", first)
+	}
+	if second := runPooled(t, pool, "class Main {}", 0); second.Output != first.Output {
+		t.Errorf("second run on JVM %q, want reused %q", second.Output, first.Output)
+	}
+}
+
+func TestJVMPoolRecyclesWorker(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxRuns int
+		sources []string
+		// reused tells whether each run after first reused JVM of previous run
+		reused []bool
+	}{
+		{name: "after max runs", maxRuns: 2, sources: []string{"", "", "", ""}, reused: []bool{true, false, true}},
+		{name: "after lingering threads", sources: []string{"LINGER", "", ""}, reused: []bool{false, true}},
+		{name: "after crash", sources: []string{"", "CRASH", ""}, reused: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newFakePool(t, 1, tt.maxRuns)
+			previous := runPooled(t, pool, tt.sources[0], 0).Output
+			for i, source := range tt.sources[1:] {
+				output := runPooled(t, pool, source, 0).Output
+				if reused := output != "" && output == previous; reused != tt.reused[i] {
+					t.Errorf("run %v reused JVM = %v, want %v", i+1, reused, tt.reused[i])
+				}
+				previous = output
+			}
+		})
+	}
+}
+
+func TestJVMPoolKillsWorkerOnTimeout(t *testing.T) {
+	pool := newFakePool(t, 1, 0)
+	pid := runPooled(t, pool, "", 0).Output
+	start := time.Now()
+	result := runPooled(t, pool, "LOOP", 200*time.Millisecond)
+	if result.Verdict != VerdictTimeLimit {
+		t.Fatalf("verdict = %v, want %v", result.Verdict, VerdictTimeLimit)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run took %v, want it stopped at time limit", elapsed)
+	}
+	if alive(t, pid) {
+		t.Errorf("JVM %v is alive after exceeding time limit", strings.TrimSpace(pid))
+	}
+	if next := runPooled(t, pool, "", 0).Output; next == pid {
+		t.Errorf("run after timeout reused killed JVM %v", pid)
+	}
+}
+
+func TestJVMPoolClose(t *testing.T) {
+	pool := newFakePool(t, 2, 0)
+	pid := runPooled(t, pool, "", 0).Output
+	pool.Close()
+	// second close does not wait for slots
+	pool.Close()
+	if alive(t, pid) {
+		t.Errorf("JVM %v is alive after pool is closed", strings.TrimSpace(pid))
+	}
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, JavaMainName), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	type executed struct {
+		result *CodeResult
+		err    error
+	}
+	done := make(chan executed, 1)
+	go func() {
+		result, err := NewPooledJavaExecutor(dir, pool).Execute()
+		done <- executed{result: result, err: err}
+	}()
+	select {
+	case res := <-done:
+		if res.err != nil || res.result.Verdict != VerdictOK || res.result.Output != "fresh\n" {
+			t.Errorf("Execute = %+v, %v, want OK on new JVM", res.result, res.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run after close blocked")
+	}
+}
+
+// alive checks whether process with pid printed by fake JVM is running
+func alive(t *testing.T, output string) bool {
+	pid, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil {
+		t.Fatalf("output %q is not pid", output)
+	}
+	return syscall.Kill(pid, 0) == nil
+}
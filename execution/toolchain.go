@@ -0,0 +1,92 @@
+package execution
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// versionTimeout limits how long printing tool version can take
+const versionTimeout = 5 * time.Second
+
+// versionArgs are arguments tools print their version with
+var versionArgs = map[string][]string{
+	"java":  {"-version"},
+	"javac": {"-version"},
+}
+
+// Tool is status of binary executor of language runs
+type Tool struct {
+	Command string `json:"command"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OK reports whether tool is present and runnable
+func (t *Tool) OK() bool {
+	return t.Error == ""
+}
+
+// Commands returns binaries executor of language runs
+func Commands(lang string) ([]string, error) {
+	switch lang {
+	case Python:
+		return []string{NewPythonExecutor("").CompileCommandName}, nil
+	case Java:
+		e := NewJavaExecutor("")
+		return []string{e.CompileCommandName, e.RunCommandName}, nil
+	case Cpp:
+		return []string{NewCppExecutor("").CompileCommandName}, nil
+	}
+	return nil, ErrUnknownLanguage
+}
+
+// Toolchain checks that every binary executor of language runs is present and
+// runnable and reports its version
+func Toolchain(lang string) ([]*Tool, error) {
+	commands, err := Commands(lang)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]*Tool, len(commands))
+	for i, command := range commands {
+		tools[i] = checkTool(command)
+	}
+	return tools, nil
+}
+
+func checkTool(command string) *Tool {
+	tool := &Tool{Command: command}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		tool.Error = err.Error()
+		return tool
+	}
+	tool.Path = path
+	args, ok := versionArgs[command]
+	if !ok {
+		args = []string{"--version"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	// some tools print version to stderr
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if err != nil {
+		tool.Error = err.Error()
+		return tool
+	}
+	tool.Version = FirstLine(string(out))
+	return tool
+}
+
+// FirstLine returns first line of text which is not blank, trimmed
+func FirstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package execution
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		lang    string
+		want    []string
+		wantErr error
+	}{
+		{lang: Python, want: []string{"python3"}},
+		{lang: Java, want: []string{"javac", "java"}},
+		{lang: Cpp, want: []string{"c++"}},
+		{lang: "cobol", wantErr: ErrUnknownLanguage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			got, err := Commands(tt.lang)
+			if err != tt.wantErr {
+				t.Fatalf("Commands(%v) error = %v, want %v", tt.lang, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Commands(%v) = %v, want %v", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolchain(t *testing.T) {
+	// only fake tools are on path, java is missing and c++ can not print its version
+	bin := writeFiles(t, map[string]string{
+		"python3": "#!/bin/sh\n[ \"$1\" = --version ] && echo 'Python 3.8.1'\n",
+		"javac":   "#!/bin/sh\n[ \"$1\" = -version ] && echo '\n  javac 11.0.2' >&2\n",
+		"c++":     "#!/bin/sh\necho 'unknown option' >&2\nexit 1\n",
+	})
+	t.Setenv("PATH", bin)
+	tests := []struct {
+		lang string
+		// versions are versions of tools, tools without version have error
+		versions []string
+	}{
+		{lang: Python, versions: []string{"Python 3.8.1"}},
+		{lang: Java, versions: []string{"javac 11.0.2", ""}},
+		{lang: Cpp, versions: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			tools, err := Toolchain(tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tools) != len(tt.versions) {
+				t.Fatalf("Toolchain(%v) returned %v tools, want %v", tt.lang, len(tools), len(tt.versions))
+			}
+			for i, tool := range tools {
+				if tool.Version != tt.versions[i] || tool.OK() != (tt.versions[i] != "") {
+					t.Errorf("tool %v = %+v, want version %q", tool.Command, tool, tt.versions[i])
+				}
+			}
+		})
+	}
+	if _, err := Toolchain("cobol"); err != ErrUnknownLanguage {
+		t.Errorf("Toolchain(cobol) error = %v, want %v", err, ErrUnknownLanguage)
+	}
+}
+
+func TestFirstLine(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "", want: ""},
+		{text: "one", want: "one"},
+		{text: "\n \n  two words \nthree\n", want: "two words"},
+	}
+	for _, tt := range tests {
+		if got := FirstLine(tt.text); got != tt.want {
+			t.Errorf("FirstLine(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/Strovala/crackview/execution"
@@ -18,6 +19,14 @@ const (
 	mainTemplate      = "main.txt"
 )
 
+// Kinds of values
+const (
+	simpleKind = "simple"
+	arrayKind  = "array"
+	setKind    = "set"
+	mapKind    = "map"
+)
+
 const (
 	intType        = "int"
 	stringType     = "string"
@@ -96,6 +105,29 @@ func value(value interface{}, valueType string) string {
 	return fmt.Sprintf("%v", value)
 }
 
+// templateFiles are templates every language needs
+var templateFiles = []string{
+	arrayTemplate,
+	setTemplate,
+	simpleTemplate,
+	mapTemplate,
+	inputArgsTemplate,
+	mainTemplate,
+}
+
+// MissingTemplates returns paths of templates of language which can not be read,
+// templates are read relative to working directory
+func MissingTemplates(lang string) []string {
+	var missing []string
+	for _, file := range templateFiles {
+		path := fmt.Sprintf("%v/%v/%v", templatesPath, lang, file)
+		if _, err := ioutil.ReadFile(path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
+
 func getTemplate(lang, file string) string {
 	dat, _ := ioutil.ReadFile(fmt.Sprintf("%v/%v/%v", templatesPath, lang, file))
 	return string(dat)
@@ -105,6 +137,8 @@ func getTemplate(lang, file string) string {
 type Argument interface {
 	ResolveType()
 	Generate(name string, lang Language) string
+	// Value returns parsed go value of argument
+	Value() interface{}
 }
 
 type argument struct {
@@ -114,6 +148,37 @@ type argument struct {
 	value     interface{}
 }
 
+func (a *argument) Value() interface{} {
+	return a.value
+}
+
+// Result describes type of value returned by solution
+type Result struct {
+	kind      string
+	keyType   string
+	valueType string
+}
+
+// NewSimpleResult creates result of simple type
+func NewSimpleResult(valueType string) *Result {
+	return &Result{kind: simpleKind, valueType: valueType}
+}
+
+// NewArrayResult creates result of array type with given element type
+func NewArrayResult(valueType string) *Result {
+	return &Result{kind: arrayKind, valueType: valueType}
+}
+
+// NewSetResult creates result of set type with given element type
+func NewSetResult(valueType string) *Result {
+	return &Result{kind: setKind, valueType: valueType}
+}
+
+// NewMapResult creates result of map type with given key and value types
+func NewMapResult(keyType, valueType string) *Result {
+	return &Result{kind: mapKind, keyType: keyType, valueType: valueType}
+}
+
 // Language is interface for generating code snippet for specific language
 type Language interface {
 	// Returns type of given type in code ex. Type("string") -> String in Java
@@ -127,7 +192,9 @@ type Language interface {
 	GenerateAddToArrayTemplate(name string, val interface{}, valType string) string
 	GenerateMapTemplate(argKeyType, argValueType string, name, value string) string
 	GenerateAddToMapTemplate(name string, keyValue interface{}, keyType string, val interface{}, valType string) string
-	Generate(inputArgs string, solution string)
+	// Returns type of value returned by solution ex. ResultType(array of int) -> int[] in Java
+	ResultType(result *Result) string
+	Generate(dir string, inputArgs string, solution string) error
 }
 
 type language struct {
@@ -186,10 +253,14 @@ func (l *python) GenerateAddToMapTemplate(name string, keyValue interface{}, key
 	return fmt.Sprintf(l.addToMapTemplate, l.GenerateValue(keyValue, keyType), l.GenerateValue(val, valType))
 }
 
-func (l *python) Generate(inputArgs string, solution string) {
+func (l *python) ResultType(result *Result) string {
+	return ""
+}
+
+func (l *python) Generate(dir string, inputArgs string, solution string) error {
 	template := l.getTemplate(mainTemplate)
 	code := fmt.Sprintf(template, solution, inputArgs)
-	_ = ioutil.WriteFile(l.mainName, []byte(code), 0644)
+	return ioutil.WriteFile(filepath.Join(dir, l.mainName), []byte(code), 0644)
 }
 
 type java struct {
@@ -245,10 +316,22 @@ func (l *java) GenerateAddToMapTemplate(name string, keyValue interface{}, keyTy
 	return fmt.Sprintf(l.addToMapTemplate, l.GenerateValue(keyValue, keyType), l.GenerateValue(val, valType))
 }
 
-func (l *java) Generate(inputArgs string, solution string) {
+func (l *java) ResultType(result *Result) string {
+	switch result.kind {
+	case arrayKind:
+		return fmt.Sprintf("%v[]", l.Type(result.valueType))
+	case setKind:
+		return fmt.Sprintf("Set<%v>", l.wrapperClass(result.valueType))
+	case mapKind:
+		return fmt.Sprintf("Map<%v, %v>", l.wrapperClass(result.keyType), l.wrapperClass(result.valueType))
+	}
+	return l.Type(result.valueType)
+}
+
+func (l *java) Generate(dir string, inputArgs string, solution string) error {
 	template := l.getTemplate(mainTemplate)
 	code := fmt.Sprintf(template, inputArgs, solution)
-	_ = ioutil.WriteFile(l.mainName, []byte(code), 0644)
+	return ioutil.WriteFile(filepath.Join(dir, l.mainName), []byte(code), 0644)
 }
 
 type cpp struct {
@@ -300,10 +383,22 @@ func (l *cpp) GenerateAddToMapTemplate(name string, keyValue interface{}, keyTyp
 	return fmt.Sprintf(l.addToMapTemplate, name, l.GenerateValue(keyValue, keyType), l.GenerateValue(val, valType))
 }
 
-func (l *cpp) Generate(inputArgs string, solution string) {
+func (l *cpp) ResultType(result *Result) string {
+	switch result.kind {
+	case arrayKind:
+		return fmt.Sprintf("vector<%v>", l.Type(result.valueType))
+	case setKind:
+		return fmt.Sprintf("set<%v>", l.Type(result.valueType))
+	case mapKind:
+		return fmt.Sprintf("map<%v, %v>", l.Type(result.keyType), l.Type(result.valueType))
+	}
+	return l.Type(result.valueType)
+}
+
+func (l *cpp) Generate(dir string, inputArgs string, solution string) error {
 	template := l.getTemplate(mainTemplate)
 	code := fmt.Sprintf(template, solution, inputArgs)
-	_ = ioutil.WriteFile(l.mainName, []byte(code), 0644)
+	return ioutil.WriteFile(filepath.Join(dir, l.mainName), []byte(code), 0644)
 }
 
 var (
@@ -332,8 +427,9 @@ var (
 	}}
 )
 
-// Generate generates code snippet for executing solution with provided arguments
-func Generate(args []Argument, lang string, solution string) {
+// Generate generates code snippet in dir for executing solution with provided
+// arguments, value returned by solution is written to execution.OutputFileName
+func Generate(dir string, args []Argument, result *Result, lang string, solution string) error {
 	template := getTemplate(lang, inputArgsTemplate)
 	var argsInit strings.Builder
 	var argsPass strings.Builder
@@ -345,6 +441,8 @@ func Generate(args []Argument, lang string, solution string) {
 		langObj = javaObj
 	case execution.Cpp:
 		langObj = cppObj
+	default:
+		return execution.ErrUnknownLanguage
 	}
 	for i, arg := range args {
 		argName := fmt.Sprintf("input_%v", i)
@@ -356,6 +454,6 @@ func Generate(args []Argument, lang string, solution string) {
 			fmt.Fprintf(&argsPass, "%v", argName)
 		}
 	}
-	inputArgs := fmt.Sprintf(template, argsInit.String(), argsPass.String())
-	langObj.Generate(inputArgs, solution)
+	inputArgs := fmt.Sprintf(template, argsInit.String(), langObj.ResultType(result), argsPass.String())
+	return langObj.Generate(dir, inputArgs, solution)
 }
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+)
+
+// TestMain runs tests from repository root where templates are
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestMissingTemplates(t *testing.T) {
+	for lang := range execution.MainNames {
+		if missing := MissingTemplates(lang); len(missing) != 0 {
+			t.Errorf("MissingTemplates(%v) = %v, want none", lang, missing)
+		}
+	}
+	if missing := MissingTemplates("cobol"); len(missing) != len(templateFiles) {
+		t.Errorf("MissingTemplates(cobol) = %v, want every template", missing)
+	}
+}
+
+func TestMissingTemplatesOfPartialLanguage(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	langDir := filepath.Join(dir, templatesPath, execution.Python)
+	if err := os.MkdirAll(langDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range templateFiles {
+		if file == mainTemplate || file == mapTemplate {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join(langDir, file), []byte("%v"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{templatesPath + "/python/" + mapTemplate, templatesPath + "/python/" + mainTemplate}
+	if missing := MissingTemplates(execution.Python); !reflect.DeepEqual(missing, want) {
+		t.Errorf("MissingTemplates(python) = %v, want %v", missing, want)
+	}
+}
+
+func TestGenerateUnknownLanguage(t *testing.T) {
+	if err := Generate(t.TempDir(), nil, nil, "cobol", ""); err != execution.ErrUnknownLanguage {
+		t.Errorf("Generate(cobol) error = %v, want %v", err, execution.ErrUnknownLanguage)
+	}
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/session"
+	"github.com/go-chi/chi"
+)
+
+// APIKeyHeader is header with static API key
+const APIKeyHeader = "X-API-Key"
+
+type contextKey string
+
+const claimsKey contextKey = "claims"
+
+// requestClaims returns claims of authenticated client, nil when authentication is disabled
+func requestClaims(r *http.Request) *auth.Claims {
+	claims, _ := r.Context().Value(claimsKey).(*auth.Claims)
+	return claims
+}
+
+// bearerToken returns token from authorization header or access_token query parameter,
+// query parameter is needed for websockets which can't set headers in browsers
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+func unauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	JSONErrorResponse(w, err.Error(), http.StatusUnauthorized)
+}
+
+// Authenticate middleware rejects requests without valid API key or signed token
+func Authenticate(a *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			var claims *auth.Claims
+			var err error
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				claims, err = a.APIKey(key)
+			} else if token := bearerToken(r); token != "" {
+				claims, err = a.Verify(token)
+			} else {
+				err = auth.ErrUnauthenticated
+			}
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey, claims)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireRole middleware allows only clients with one of roles,
+// every request passes when authentication is disabled
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			claims := requestClaims(r)
+			if claims != nil && !hasRole(claims, roles) {
+				JSONErrorResponse(w, session.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func hasRole(claims *auth.Claims, roles []string) bool {
+	for _, role := range roles {
+		if claims.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionRole returns session role granted by request claims, admins act as interviewers
+// and session tokens act only in their own session
+func sessionRole(r *http.Request, sessionID string) string {
+	claims := requestClaims(r)
+	if claims == nil {
+		return ""
+	}
+	if claims.Role == auth.RoleAdmin {
+		return session.RoleInterviewer
+	}
+	if claims.SessionID == sessionID {
+		return claims.Role
+	}
+	return ""
+}
+
+func newAuthHandler(a *auth.Authenticator) http.Handler {
+	h := authHandler{auth: a}
+	mux := chi.NewMux()
+	mux.With(RequireRole(auth.RoleAdmin, auth.RoleInterviewer)).Post("/tokens", errorHandler(h.Token))
+	mux.Get("/me", errorHandler(h.Me))
+	return mux
+}
+
+type authHandler struct {
+	auth *auth.Authenticator
+}
+
+// TokenRequest is request for signed session token, TTL is in minutes and it
+// is capped by auth.maxTokenTTL from config
+type TokenRequest struct {
+	Subject   string `json:"subject"`
+	Role      string `json:"role"`
+	SessionID string `json:"sessionId"`
+	TTL       int    `json:"ttl"`
+}
+
+// TokenResponse is signed session token
+type TokenResponse struct {
+	Token  string       `json:"token"`
+	Claims *auth.Claims `json:"claims"`
+}
+
+// Token signs new token, only admins can issue admin tokens or tokens without session
+// and interviewers with session token can issue tokens only for their session
+func (h *authHandler) Token(w http.ResponseWriter, r *http.Request) error {
+	data := &TokenRequest{}
+	if err := Unmarshal(data, r); err != nil {
+		return BadRequest(err)
+	}
+	if !auth.ValidRole(data.Role) {
+		return BadRequest(auth.ErrUnknownRole)
+	}
+	caller := requestClaims(r)
+	if caller != nil && caller.Role != auth.RoleAdmin && (data.Role == auth.RoleAdmin || data.SessionID == "" ||
+		(caller.SessionID != "" && caller.SessionID != data.SessionID)) {
+		return &StatusError{Code: http.StatusForbidden, Err: session.ErrForbidden}
+	}
+	ttl, err := h.auth.TTL(time.Duration(data.TTL) * time.Minute)
+	if err != nil {
+		return BadRequest(err)
+	}
+	claims := &auth.Claims{
+		Subject:   data.Subject,
+		Role:      data.Role,
+		SessionID: data.SessionID,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+	token, err := h.auth.Sign(claims)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, &TokenResponse{Token: token, Claims: claims}, http.StatusCreated)
+	return nil
+}
+
+// Me returns claims of authenticated client
+func (h *authHandler) Me(w http.ResponseWriter, r *http.Request) error {
+	JSONResponse(w, requestClaims(r), http.StatusOK)
+	return nil
+}
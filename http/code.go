@@ -1,63 +1,218 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
+	"time"
 
 	"github.com/Strovala/crackview/execution"
-	"github.com/Strovala/crackview/generator"
-	"github.com/Strovala/crackview/parser"
+	"github.com/Strovala/crackview/logging"
+	"github.com/Strovala/crackview/ratelimit"
+	"github.com/Strovala/crackview/runner"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
 	"github.com/go-chi/chi"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
-func newCodeHandler() http.Handler {
-	c := code{}
+// Server sent events
+const (
+	eventOutput = "output"
+	eventResult = "result"
+	eventError  = "error"
+)
+
+// errUnexpected is sent to stream when run panics
+var errUnexpected = errors.New("Unexpected error occurred")
+
+func newCodeHandler(s *store.Store, hub *session.Hub, executions *ratelimit.Semaphore) http.Handler {
+	c := code{store: s, hub: hub}
 	mux := chi.NewMux()
 	mux.Get("/info", errorHandler(c.Info))
-	mux.Post("/execute", errorHandler(c.Execute))
+	mux.Get("/flags", errorHandler(c.Flags))
+	limited := mux.With(LimitExecutions(executions), NoWriteTimeout)
+	limited.Post("/execute", errorHandler(c.Execute))
+	limited.Post("/execute/stream", errorHandler(c.ExecuteStream))
 	return mux
 }
 
-type code struct{}
+type code struct {
+	store *store.Store
+	hub   *session.Hub
+}
 
 func (c *code) Info(w http.ResponseWriter, r *http.Request) error {
 	JSONResponse(w, fmt.Sprintf("Of languages we support: %v", viper.GetStringSlice("languages")), http.StatusOK)
 	return nil
 }
 
+// Flags returns flags every language runs with and flags requests can add
+func (c *code) Flags(w http.ResponseWriter, r *http.Request) error {
+	data := make(map[string]*execution.LanguageFlags)
+	for lang := range execution.MainNames {
+		flags, err := runner.LanguageFlags(lang)
+		if err != nil {
+			return err
+		}
+		data[lang] = flags
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
 func (c *code) Execute(w http.ResponseWriter, r *http.Request) error {
 	var data CodeRequest
 	if err := Unmarshal(&data, r); err != nil {
 		return err
 	}
-	args, err := parser.Parse(data.Input)
-	if err != nil {
+	if err := attach(c.store, c.hub, r, &data, ""); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
 		return err
 	}
-	var executor execution.Executor
-	switch data.Lang {
-	case execution.Python:
-		generator.Generate(args, execution.Python, data.Text)
-		executor = execution.NewPythonExecutor()
-	case execution.Java:
-		generator.Generate(args, execution.Java, data.Text)
-		executor = execution.NewJavaExecutor()
-	case execution.Cpp:
-		generator.Generate(args, execution.Cpp, data.Text)
-		executor = execution.NewCppExecutor()
+	logger := logging.FromContext(r.Context())
+	req := data.runnerRequest(logger)
+	if err := runner.Validate(req); err != nil {
+		return BadRequest(err)
 	}
-	resp, err := executor.Execute()
+	start := time.Now()
+	resp, err := runner.Run(req)
 	if err != nil {
 		return err
 	}
+	record(logger, c.store, data.execution(store.KindExecute, resp), start)
 	JSONResponse(w, *resp, http.StatusOK)
 	return nil
 }
 
+// ExecuteStream executes code and sends its output as server sent events while
+// it is running, last event carries the result
+func (c *code) ExecuteStream(w http.ResponseWriter, r *http.Request) error {
+	var data CodeRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	if err := attach(c.store, c.hub, r, &data, ""); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
+		return err
+	}
+	logger := logging.FromContext(r.Context())
+	req := data.runnerRequest(logger)
+	if err := runner.Validate(req); err != nil {
+		return BadRequest(err)
+	}
+	stream, err := NewEventStream(w)
+	if err != nil {
+		return err
+	}
+
+	// run is stopped once client disconnects
+	ctx := r.Context()
+	req.Context = ctx
+	chunks := make(chan execution.Chunk)
+	req.Listener = func(chunk execution.Chunk) {
+		select {
+		case chunks <- chunk:
+		case <-ctx.Done():
+		}
+	}
+	type executed struct {
+		result *execution.CodeResult
+		err    error
+	}
+	done := make(chan executed, 1)
+	start := time.Now()
+	go func() {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				logger.Error("recovered from panic",
+					zap.Any("panic", rvr),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				done <- executed{err: errUnexpected}
+			}
+		}()
+		result, err := runner.Run(req)
+		done <- executed{result: result, err: err}
+	}()
+
+	for {
+		select {
+		case chunk := <-chunks:
+			stream.Send(eventOutput, chunk)
+		case res := <-done:
+			if res.err != nil {
+				stream.Send(eventError, res.err.Error())
+				return nil
+			}
+			record(logger, c.store, data.execution(store.KindExecute, res.result), start)
+			stream.Send(eventResult, *res.result)
+			return nil
+		case <-ctx.Done():
+			logger.Info("client disconnected, stopped streamed run")
+			return nil
+		}
+	}
+}
+
 // CodeRequest is DTO for request for code run
 type CodeRequest struct {
-	Input string `json:"input"`
-	Text  string `json:"text"`
-	Lang  string `json:"lang"`
+	Input   string `json:"input"`
+	Text    string `json:"text"`
+	Lang    string `json:"lang"`
+	Returns string `json:"returns"`
+	// Flags are added to flags language runs with, they have to be allowed by config
+	Flags *execution.Flags `json:"flags,omitempty"`
+	// SessionID attaches request to interview session, session token has to
+	// be sent in X-Session-Token header, CandidateID is taken from session when
+	// candidate runs code. Both are stored with execution in history. Request
+	// with session and without text runs shared session document
+	SessionID   string `json:"sessionId"`
+	CandidateID string `json:"candidateId"`
+}
+
+// execution creates execution for history from request and its result
+func (d *CodeRequest) execution(kind string, result *execution.CodeResult) *store.Execution {
+	return &store.Execution{
+		Kind:        kind,
+		SessionID:   d.SessionID,
+		CandidateID: d.CandidateID,
+		Lang:        d.Lang,
+		Code:        d.Text,
+		Input:       d.Input,
+		Verdict:     result.Verdict,
+		Result:      result,
+	}
+}
+
+func (d *CodeRequest) runnerRequest(logger *zap.Logger) *runner.Request {
+	return &runner.Request{
+		Logger:   logger,
+		Lang:     d.Lang,
+		Solution: d.Text,
+		Input:    d.Input,
+		Returns:  d.Returns,
+		Flags:    d.Flags,
+	}
+}
+
+// validate checks that language of request is supported and that requested
+// flags are allowed for it
+func (d *CodeRequest) validate() error {
+	if _, ok := execution.MainNames[d.Lang]; !ok {
+		return BadRequest(execution.ErrUnknownLanguage)
+	}
+	if d.Flags == nil {
+		return nil
+	}
+	if _, err := runner.SelectFlags(d.Lang, d.Flags); err != nil {
+		return BadRequest(err)
+	}
+	return nil
 }
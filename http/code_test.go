@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+)
+
+type event struct {
+	name string
+	data string
+}
+
+// readEvents reads server sent events of stream until it ends
+func readEvents(t *testing.T, resp *http.Response) []event {
+	var events []event
+	var current event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		case line == "" && current.name != "":
+			events = append(events, current)
+			current = event{}
+		}
+	}
+	return events
+}
+
+func postStream(ctx context.Context, t *testing.T, url, solution string) *http.Response {
+	body, err := json.Marshal(CodeRequest{Input: "3 => int", Text: solution, Lang: execution.Python, Returns: "int"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/execute/stream", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	return resp
+}
+
+func TestExecuteStreamEventOrder(t *testing.T) {
+	requirePython(t)
+	server := httptest.NewServer(NewCrackviewHandler(Options{Store: newTestStore(t)}))
+	defer server.Close()
+
+	solution := "class Solution:\n    def code(n):\n        for i in range(n):\n            print(i, flush=True)\n        return n\n"
+	resp := postStream(context.Background(), t, server.URL, solution)
+	defer resp.Body.Close()
+	events := readEvents(t, resp)
+	if len(events) < 2 {
+		t.Fatalf("events = %+v, want output followed by result", events)
+	}
+	var output strings.Builder
+	for _, e := range events[:len(events)-1] {
+		if e.name != eventOutput {
+			t.Fatalf("event %v before result, want only %v", e.name, eventOutput)
+		}
+		var chunk execution.Chunk
+		if err := json.Unmarshal([]byte(e.data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+		if chunk.Stream == execution.Stdout {
+			output.WriteString(chunk.Text)
+		}
+	}
+	if output.String() != "0\n1\n2\n" {
+		t.Errorf("streamed output = %q, want %q", output.String(), "0\n1\n2\n")
+	}
+	last := events[len(events)-1]
+	if last.name != eventResult {
+		t.Fatalf("last event = %v, want %v", last.name, eventResult)
+	}
+	var result execution.CodeResult
+	if err := json.Unmarshal([]byte(last.data), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != execution.VerdictOK || strings.TrimSpace(result.Result) != "3" {
+		t.Errorf("result = %+v, want OK with 3", result)
+	}
+}
+
+func TestExecuteStreamStopsOnDisconnect(t *testing.T) {
+	requirePython(t)
+	finished := make(chan struct{})
+	handler := NewCrackviewHandler(Options{Store: newTestStore(t)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		close(finished)
+	}))
+	defer server.Close()
+
+	// run without time limit only ends when client disconnects
+	solution := "import time\nclass Solution:\n    def code(n):\n        while True:\n            print(n, flush=True)\n            time.sleep(0.01)\n"
+	ctx, cancel := context.WithCancel(context.Background())
+	resp := postStream(ctx, t, server.URL, solution)
+	defer resp.Body.Close()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "event: "+eventOutput+"\n" {
+		t.Fatalf("first line = %q, %v, want output event", line, err)
+	}
+	cancel()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not stop run after client disconnected")
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Strovala/crackview/session"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 64 * 1024
+)
+
+// newUpgrader creates upgrader which accepts connections from pages served by
+// same host and from allowed origins, see checkOrigin
+func newUpgrader(allowed []string) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return checkOrigin(r, allowed) },
+	}
+}
+
+// checkOrigin reports whether websocket request can be accepted, browsers always
+// send Origin so other sites can not connect on behalf of participant. Requests
+// without Origin come from clients other than browsers and are accepted
+func checkOrigin(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// Collaborate connects session participant to shared document over websocket,
+// token is sent as query parameter since browsers can not set websocket headers
+func (h *sessions) Collaborate(w http.ResponseWriter, r *http.Request) error {
+	sess, role, err := authorize(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	if sess.State(time.Now()) == session.StateEnded {
+		return sessionError(session.ErrNotActive)
+	}
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader already responded with error
+		return nil
+	}
+	client, err := h.hub.Join(sess.ID, role)
+	if err != nil {
+		conn.Close()
+		return nil
+	}
+	go writeMessages(conn, client)
+	readMessages(conn, h.hub, client)
+	return nil
+}
+
+// readMessages passes messages from connection to hub until connection is closed
+func readMessages(conn *websocket.Conn, hub *session.Hub, client *session.Client) {
+	defer func() {
+		hub.Leave(client)
+		conn.Close()
+	}()
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		var msg session.Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		hub.Handle(client, &msg)
+	}
+}
+
+// writeMessages writes messages hub sends to client and keeps connection alive
+func writeMessages(conn *websocket.Conn, client *session.Client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+	for {
+		select {
+		case msg := <-client.Send:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-client.Done:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
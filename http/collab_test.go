@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/session"
+	"github.com/gorilla/websocket"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		allowed []string
+		want    bool
+	}{
+		{name: "without origin", want: true},
+		{name: "same host", origin: "http://crackview.test:8888", want: true},
+		{name: "same host other scheme", origin: "https://CRACKVIEW.test:8888", want: true},
+		{name: "other port", origin: "http://crackview.test:9999", want: false},
+		{name: "other site", origin: "https://evil.test", want: false},
+		{name: "allowed", origin: "https://ide.test", allowed: []string{"https://ide.test/"}, want: true},
+		{name: "not allowed", origin: "https://evil.test", allowed: []string{"https://ide.test"}, want: false},
+		{name: "every origin", origin: "https://evil.test", allowed: []string{"*"}, want: true},
+		{name: "invalid", origin: "http://%zz", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://crackview.test:8888/sessions/id/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := checkOrigin(r, tt.allowed); got != tt.want {
+				t.Errorf("checkOrigin(%q, %v) = %v, want %v", tt.origin, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollaborateOrigin(t *testing.T) {
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewCrackviewHandler(Options{
+		Problems:       bank,
+		Store:          newTestStore(t),
+		AllowedOrigins: []string{"https://ide.test"},
+	}))
+	t.Cleanup(server.Close)
+	var created SessionResponse
+	if status := request(t, http.MethodPost, server.URL+"/sessions", "", SessionRequest{Title: "Screen"}, &created); status != http.StatusCreated {
+		t.Fatalf("create status = %v", status)
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/sessions/" + created.ID + "/ws?token=" + created.CandidateToken
+
+	tests := []struct {
+		name   string
+		origin string
+		want   int
+	}{
+		{name: "same host", origin: server.URL, want: http.StatusSwitchingProtocols},
+		{name: "allowed", origin: "https://ide.test", want: http.StatusSwitchingProtocols},
+		{name: "other site", origin: "https://evil.test", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {tt.origin}})
+			if resp == nil {
+				t.Fatalf("dial failed: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %v, want %v", resp.StatusCode, tt.want)
+			}
+			if conn == nil {
+				return
+			}
+			defer conn.Close()
+			var msg session.Message
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatal(err)
+			}
+			if msg.Type != session.MessageSnapshot || msg.Role != session.RoleCandidate {
+				t.Errorf("first message = %+v, want candidate snapshot", msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/store"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// ErrNegativeLimit is returned when executions are listed with negative limit
+var ErrNegativeLimit = errors.New("Limit can not be negative")
+
+func newExecutionsHandler(s *store.Store) http.Handler {
+	e := executions{store: s}
+	mux := chi.NewMux()
+	mux.Use(RequireRole(auth.RoleAdmin, auth.RoleInterviewer))
+	mux.Get("/", errorHandler(e.List))
+	mux.Get("/{id}", errorHandler(e.Get))
+	return mux
+}
+
+type executions struct {
+	store *store.Store
+}
+
+// List returns executions filtered by query parameters
+func (e *executions) List(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	filter := store.ExecutionFilter{
+		Kind:        query.Get("kind"),
+		SessionID:   query.Get("sessionId"),
+		CandidateID: query.Get("candidateId"),
+		ProblemID:   query.Get("problemId"),
+		Lang:        query.Get("lang"),
+		Verdict:     query.Get("verdict"),
+	}
+	var err error
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return BadRequest(err)
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return BadRequest(err)
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return BadRequest(err)
+		}
+		if filter.Limit < 0 {
+			return BadRequest(ErrNegativeLimit)
+		}
+	}
+	data, err := e.store.Executions(filter)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+func (e *executions) Get(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return BadRequest(err)
+	}
+	data, err := e.store.Execution(id)
+	if err == store.ErrNotFound {
+		return NotFound(err)
+	}
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+// record persists execution which started at start, failing to persist it
+// does not fail the request since candidate already has the result
+func record(logger *zap.Logger, s *store.Store, e *store.Execution, start time.Time) {
+	e.CreatedAt = start
+	e.Duration = int64(time.Since(start) / time.Millisecond)
+	if err := s.SaveExecution(e); err != nil {
+		logger.Error("unable to save execution", zap.Error(err))
+	}
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/store"
+)
+
+func TestExecutionsDoNotLeakHiddenTests(t *testing.T) {
+	requirePython(t)
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewCrackviewHandler(Options{Problems: bank, Store: newTestStore(t)}))
+	defer server.Close()
+
+	// wrong solution so results of every test carry actual output
+	body, err := json.Marshal(CodeRequest{Lang: execution.Python, Text: "class Solution:\n    def code(nums, target):\n        return [7, 7]\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(server.URL+"/problems/two-sum/submit", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("submit status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+
+	var listed []*store.Execution
+	getJSON(t, server.URL+"/executions", &listed)
+	if len(listed) != 1 || listed[0].Submission == nil {
+		t.Fatalf("executions = %+v, want one submission", listed)
+	}
+	var single store.Execution
+	getJSON(t, fmt.Sprintf("%v/executions/%v", server.URL, listed[0].ID), &single)
+	for name, e := range map[string]*store.Execution{"list": listed[0], "get": &single} {
+		hidden := 0
+		for _, test := range e.Submission.Tests {
+			if !test.Hidden {
+				if test.Test == nil {
+					t.Errorf("%v: visible test %v has no data", name, test.Index)
+				}
+				continue
+			}
+			hidden++
+			if test.Test != nil || test.Result != nil {
+				t.Errorf("%v: hidden test %v leaks %+v %+v", name, test.Index, test.Test, test.Result)
+			}
+		}
+		if hidden == 0 {
+			t.Errorf("%v: submission has no hidden tests", name)
+		}
+	}
+}
+
+// getJSON decodes data of JSON response of GET request to url into dest
+func getJSON(t *testing.T, url string, dest interface{}) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %v status = %v, want %v", url, resp.StatusCode, http.StatusOK)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&struct{ Data interface{} }{Data: dest}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+)
+
+// readinessTTL is how long readiness is reused, checking it runs every toolchain
+const readinessTTL = 15 * time.Second
+
+// lastReadiness holds latest readiness and time it was checked at
+var lastReadiness struct {
+	sync.Mutex
+	readiness *Readiness
+	checkedAt time.Time
+}
+
+// LanguageStatus is readiness of toolchain of language
+type LanguageStatus struct {
+	Lang  string            `json:"lang"`
+	Ready bool              `json:"ready"`
+	Tools []*execution.Tool `json:"tools"`
+}
+
+// Readiness is readiness of server to run solutions in every supported language
+type Readiness struct {
+	Ready     bool              `json:"ready"`
+	Languages []*LanguageStatus `json:"languages"`
+}
+
+// Health reports that server is up, it is never authenticated
+func Health(w http.ResponseWriter, r *http.Request) {
+	JSONResponse(w, "ok", http.StatusOK)
+}
+
+// Ready reports whether toolchains of every supported language are present and
+// runnable, server which is not ready responds with 503
+func Ready(w http.ResponseWriter, r *http.Request) {
+	readiness := cachedReadiness()
+	code := http.StatusOK
+	if !readiness.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	JSONResponse(w, readiness, code)
+}
+
+// cachedReadiness returns readiness checked at most readinessTTL ago, concurrent
+// callers wait for single check
+func cachedReadiness() *Readiness {
+	lastReadiness.Lock()
+	defer lastReadiness.Unlock()
+	if lastReadiness.readiness == nil || time.Since(lastReadiness.checkedAt) > readinessTTL {
+		lastReadiness.readiness = checkReadiness()
+		lastReadiness.checkedAt = time.Now()
+	}
+	return lastReadiness.readiness
+}
+
+func checkReadiness() *Readiness {
+	var langs []string
+	for lang := range execution.MainNames {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	readiness := &Readiness{Ready: true, Languages: make([]*LanguageStatus, len(langs))}
+	var wg sync.WaitGroup
+	for i, lang := range langs {
+		wg.Add(1)
+		go func(i int, lang string) {
+			defer wg.Done()
+			status := &LanguageStatus{Lang: lang, Ready: true}
+			status.Tools, _ = execution.Toolchain(lang)
+			for _, tool := range status.Tools {
+				status.Ready = status.Ready && tool.OK()
+			}
+			readiness.Languages[i] = status
+		}(i, lang)
+	}
+	wg.Wait()
+	for _, status := range readiness.Languages {
+		readiness.Ready = readiness.Ready && status.Ready
+	}
+	return readiness
+}
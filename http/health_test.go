@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewCrackviewHandler(Options{Store: newTestStore(t)}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestReady(t *testing.T) {
+	bin := t.TempDir()
+	tool := "#!/bin/sh\necho \"$0 1.0\"\n"
+	for _, name := range []string{"python3", "javac", "java"} {
+		if err := ioutil.WriteFile(filepath.Join(bin, name), []byte(tool), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	handler := NewCrackviewHandler(Options{Store: newTestStore(t)})
+	ready := func() (int, *Readiness) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		readiness := &Readiness{}
+		if err := json.NewDecoder(w.Body).Decode(&struct{ Data interface{} }{Data: readiness}); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, readiness
+	}
+	resetReadiness := func() {
+		lastReadiness.Lock()
+		lastReadiness.readiness = nil
+		lastReadiness.Unlock()
+	}
+	resetReadiness()
+	defer resetReadiness()
+
+	code, readiness := ready()
+	if code != http.StatusServiceUnavailable || readiness.Ready {
+		t.Fatalf("readiness without c++ = %v %+v, want not ready", code, readiness)
+	}
+	want := map[string]bool{"cpp": false, "java": true, "python": true}
+	if len(readiness.Languages) != len(want) {
+		t.Fatalf("readiness has %v languages, want %v", len(readiness.Languages), len(want))
+	}
+	for _, status := range readiness.Languages {
+		if status.Ready != want[status.Lang] {
+			t.Errorf("%v ready = %v, want %v", status.Lang, status.Ready, want[status.Lang])
+		}
+		for _, tool := range status.Tools {
+			if tool.OK() && tool.Version != filepath.Join(bin, tool.Command)+" 1.0" {
+				t.Errorf("%v version = %q", tool.Command, tool.Version)
+			}
+		}
+	}
+
+	// readiness is reused until it expires
+	if err := ioutil.WriteFile(filepath.Join(bin, "c++"), []byte(tool), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("cached status = %v, want %v", code, http.StatusServiceUnavailable)
+	}
+	resetReadiness()
+	if code, readiness := ready(); code != http.StatusOK || !readiness.Ready {
+		t.Errorf("readiness with every tool = %v %+v, want ready", code, readiness)
+	}
+}
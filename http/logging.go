@@ -0,0 +1,40 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Strovala/crackview/logging"
+	"github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is response header with id of request
+const RequestIDHeader = "X-Request-Id"
+
+// RequestLogger middleware puts logger tagged with request id into request
+// context and logs every request once it is served, it expects request id
+// to be set by chi RequestID middleware
+func RequestLogger(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			requestID := middleware.GetReqID(r.Context())
+			w.Header().Set(RequestIDHeader, requestID)
+			requestLogger := logger.With(zap.String("requestId", requestID))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+			defer func() {
+				requestLogger.Info("served request",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("remote", r.RemoteAddr),
+					zap.Int("status", ww.Status()),
+					zap.Int("bytes", ww.BytesWritten()),
+					zap.Duration("duration", time.Since(start)),
+				)
+			}()
+			next.ServeHTTP(ww, r.WithContext(logging.WithLogger(r.Context(), requestLogger)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
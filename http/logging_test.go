@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/logging"
+	"github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// serveLogged serves request with handler behind request id, logger and
+// recoverer middlewares and returns response with logged entries
+func serveLogged(handler http.HandlerFunc) (*httptest.ResponseRecorder, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	h := middleware.RequestID(RequestLogger(zap.New(core))(JSONRecoverer(handler)))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/problems", nil))
+	return w, logs
+}
+
+func TestRequestLogger(t *testing.T) {
+	w, logs := serveLogged(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handled")
+		w.WriteHeader(http.StatusTeapot)
+	})
+	requestID := w.Header().Get(RequestIDHeader)
+	if requestID == "" {
+		t.Fatal("response has no request id")
+	}
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("logged %v entries, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		if fields := entry.ContextMap(); fields["requestId"] != requestID {
+			t.Errorf("entry %q has request id %v, want %v", entry.Message, fields["requestId"], requestID)
+		}
+	}
+	served := entries[1].ContextMap()
+	if entries[1].Message != "served request" || served["status"] != int64(http.StatusTeapot) || served["path"] != "/problems" || served["method"] != http.MethodGet {
+		t.Errorf("last entry = %v %v, want served request with status and path", entries[1].Message, served)
+	}
+}
+
+func TestJSONRecovererLogs(t *testing.T) {
+	w, logs := serveLogged(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %v, want %v", w.Code, http.StatusInternalServerError)
+	}
+	recovered := logs.FilterMessage("recovered from panic").All()
+	if len(recovered) != 1 {
+		t.Fatalf("logged %v panics, want 1", len(recovered))
+	}
+	fields := recovered[0].ContextMap()
+	if recovered[0].Level != zapcore.ErrorLevel || fields["panic"] != "boom" || fields["stack"] == "" || fields["requestId"] != w.Header().Get(RequestIDHeader) {
+		t.Errorf("panic entry = %v %v, want error with panic, stack and request id", recovered[0].Level, fields)
+	}
+}
+
+func TestExecutionLogsRequestID(t *testing.T) {
+	requirePython(t)
+	core, logs := observer.New(zapcore.InfoLevel)
+	server := httptest.NewServer(NewCrackviewHandler(Options{Store: newTestStore(t), Logger: zap.New(core)}))
+	defer server.Close()
+
+	body, err := json.Marshal(CodeRequest{Input: "3 => int", Text: "class Solution:\n    def code(n):\n        return n\n", Lang: execution.Python, Returns: "int"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(server.URL+"/execute", "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	executed := logs.FilterMessage("executed code").All()
+	if len(executed) != 1 {
+		t.Fatalf("logged %v executions, want 1", len(executed))
+	}
+	fields := executed[0].ContextMap()
+	if fields["requestId"] != resp.Header.Get(RequestIDHeader) || fields["lang"] != execution.Python || fields["verdict"] != execution.VerdictOK {
+		t.Errorf("execution entry = %v, want request id, language and verdict", fields)
+	}
+	for _, field := range []string{"compileTime", "runTime", "duration"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("execution entry has no %v", field)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/metrics"
+	"github.com/go-chi/chi"
+)
+
+func TestInstrument(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(Instrument)
+	router.Get("/instrumented/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	router.Get("/implicit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	for _, path := range []string{"/instrumented/1", "/instrumented/2", "/implicit", "/missing/1", "/missing/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := w.Body.String()
+	tests := []struct {
+		method string
+		route  string
+		status int
+		count  int
+	}{
+		{method: http.MethodGet, route: "/instrumented/{id}", status: http.StatusCreated, count: 2},
+		{method: http.MethodGet, route: "/implicit", status: http.StatusOK, count: 1},
+		{method: http.MethodPost, route: "unmatched", status: http.StatusMethodNotAllowed, count: 3},
+		{method: http.MethodGet, route: "unmatched", status: http.StatusNotFound, count: 2},
+	}
+	for _, tt := range tests {
+		line := fmt.Sprintf(`crackview_http_request_duration_seconds_count{method=%q,route=%q,status="%v"} %v`, tt.method, tt.route, tt.status, tt.count)
+		if !strings.Contains(out, line) {
+			t.Errorf("metrics do not contain %v", line)
+		}
+	}
+}
+
+func TestMetricsRequiresAdmin(t *testing.T) {
+	authenticator, err := auth.New(auth.Config{Secret: "secret", Keys: []auth.Key{
+		{Name: "admin", Key: "admin-key", Role: auth.RoleAdmin},
+		{Name: "interviewer", Key: "interviewer-key", Role: auth.RoleInterviewer},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewCrackviewHandler(Options{Store: newTestStore(t), Auth: authenticator}))
+	defer server.Close()
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{key: "", want: http.StatusUnauthorized},
+		{key: "interviewer-key", want: http.StatusForbidden},
+		{key: "admin-key", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/metrics", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.key != "" {
+			req.Header.Set(APIKeyHeader, tt.key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("status with key %q = %v, want %v", tt.key, resp.StatusCode, tt.want)
+		}
+		if tt.want == http.StatusOK && !strings.Contains(string(body), "crackview_http_request_duration_seconds") {
+			t.Errorf("metrics response does not contain crackview metrics:\n%s", body)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/logging"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/ratelimit"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
+	"github.com/go-chi/chi"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func newProblemsHandler(bank *problem.Bank, s *store.Store, hub *session.Hub, executions *ratelimit.Semaphore) http.Handler {
+	p := problems{bank: bank, store: s, hub: hub, executions: executions}
+	mux := chi.NewMux()
+	mux.Get("/", errorHandler(p.List))
+	mux.Get("/{id}", errorHandler(p.Get))
+	limited := mux.With(LimitExecutions(executions), NoWriteTimeout)
+	limited.Post("/{id}/run", errorHandler(p.Run))
+	limited.Post("/{id}/submit", errorHandler(p.Submit))
+	staff := limited.With(RequireRole(auth.RoleAdmin, auth.RoleInterviewer))
+	staff.Post("/{id}/stress", errorHandler(p.Stress))
+	staff.Post("/{id}/complexity", errorHandler(p.Complexity))
+	return mux
+}
+
+type problems struct {
+	bank       *problem.Bank
+	store      *store.Store
+	hub        *session.Hub
+	executions *ratelimit.Semaphore
+}
+
+// record persists submission of solution to problem in history, hidden tests
+// are redacted since history is served back to clients
+func (p *problems) record(logger *zap.Logger, kind string, found *problem.Problem, data *CodeRequest, resp *problem.SubmissionResult, start time.Time) {
+	record(logger, p.store, &store.Execution{
+		Kind:        kind,
+		SessionID:   data.SessionID,
+		CandidateID: data.CandidateID,
+		ProblemID:   found.ID,
+		Lang:        data.Lang,
+		Code:        data.Text,
+		Verdict:     resp.Verdict,
+		Submission:  resp.Redact(),
+	}, start)
+}
+
+func (p *problems) problem(r *http.Request) (*problem.Problem, error) {
+	found, err := p.bank.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, NotFound(err)
+	}
+	return found, nil
+}
+
+func (p *problems) List(w http.ResponseWriter, r *http.Request) error {
+	var data []ProblemSummary
+	for _, found := range p.bank.List() {
+		data = append(data, ProblemSummary{ID: found.ID, Title: found.Title})
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+func (p *problems) Get(w http.ResponseWriter, r *http.Request) error {
+	found, err := p.problem(r)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, ProblemResponse{Problem: found, Examples: found.Examples()}, http.StatusOK)
+	return nil
+}
+
+// Run runs solution on visible examples only
+func (p *problems) Run(w http.ResponseWriter, r *http.Request) error {
+	found, err := p.problem(r)
+	if err != nil {
+		return err
+	}
+	var data CodeRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	if err := attach(p.store, p.hub, r, &data, found.ID); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
+		return err
+	}
+	logger := logging.FromContext(r.Context())
+	start := time.Now()
+	resp, err := found.Run(data.Lang, data.Text, data.Flags, logger)
+	if err != nil {
+		return err
+	}
+	p.record(logger, store.KindRun, found, &data, resp, start)
+	JSONResponse(w, resp, http.StatusOK)
+	return nil
+}
+
+// Submit runs solution on all tests, hidden test data is never returned
+func (p *problems) Submit(w http.ResponseWriter, r *http.Request) error {
+	found, err := p.problem(r)
+	if err != nil {
+		return err
+	}
+	var data CodeRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	if err := attach(p.store, p.hub, r, &data, found.ID); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
+		return err
+	}
+	logger := logging.FromContext(r.Context())
+	start := time.Now()
+	resp, err := found.Submit(data.Lang, data.Text, data.Flags, logger)
+	if err != nil {
+		return err
+	}
+	p.record(logger, store.KindSubmit, found, &data, resp, start)
+	JSONResponse(w, resp.Redact(), http.StatusOK)
+	return nil
+}
+
+// StressRequest is DTO for stress run of solution, Duration is in milliseconds
+// and it is capped by stress.maxDuration from config
+type StressRequest struct {
+	CodeRequest
+	ReferenceLang string `json:"referenceLang"`
+	Iterations    int    `json:"iterations"`
+	Duration      int    `json:"duration"`
+	Seed          int64  `json:"seed"`
+}
+
+// Stress compares solution with reference solution on random inputs, request
+// with session and without text stresses code candidate is writing
+func (p *problems) Stress(w http.ResponseWriter, r *http.Request) error {
+	found, err := p.problem(r)
+	if err != nil {
+		return err
+	}
+	var data StressRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	if err := attach(p.store, p.hub, r, &data.CodeRequest, found.ID); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
+		return err
+	}
+	// request holds one execution slot, every other worker needs its own
+	extra, release := p.executions.AcquireUpTo(clientKey(r), runtime.NumCPU()-1)
+	defer release()
+	resp, err := found.Stress(problem.StressOptions{
+		Lang:          data.Lang,
+		Solution:      data.Text,
+		Flags:         data.Flags,
+		ReferenceLang: data.ReferenceLang,
+		Iterations:    data.Iterations,
+		Duration:      maxDuration(data.Duration, "stress.maxDuration"),
+		Workers:       1 + extra,
+		Seed:          data.Seed,
+		Logger:        logging.FromContext(r.Context()),
+	})
+	if err == problem.ErrNoReferences || err == problem.ErrNoSignatureArgs {
+		return BadRequest(err)
+	}
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, resp, http.StatusOK)
+	return nil
+}
+
+// ComplexityRequest is DTO for complexity estimation of solution, Duration is
+// in milliseconds and it is capped by complexity.maxDuration from config
+type ComplexityRequest struct {
+	CodeRequest
+	MinSize  int   `json:"minSize"`
+	MaxSize  int   `json:"maxSize"`
+	Repeats  int   `json:"repeats"`
+	Duration int   `json:"duration"`
+	Seed     int64 `json:"seed"`
+}
+
+// Complexity estimates time complexity class of solution, request with
+// session and without text estimates code candidate is writing
+func (p *problems) Complexity(w http.ResponseWriter, r *http.Request) error {
+	found, err := p.problem(r)
+	if err != nil {
+		return err
+	}
+	var data ComplexityRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	if err := attach(p.store, p.hub, r, &data.CodeRequest, found.ID); err != nil {
+		return err
+	}
+	if err := data.validate(); err != nil {
+		return err
+	}
+	resp, err := found.Complexity(problem.ComplexityOptions{
+		Lang:     data.Lang,
+		Solution: data.Text,
+		Flags:    data.Flags,
+		MinSize:  data.MinSize,
+		MaxSize:  data.MaxSize,
+		Repeats:  data.Repeats,
+		Duration: maxDuration(data.Duration, "complexity.maxDuration"),
+		Seed:     data.Seed,
+		Logger:   logging.FromContext(r.Context()),
+	})
+	if err == problem.ErrNoSignatureArgs || err == problem.ErrNotScalable {
+		return BadRequest(err)
+	}
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, resp, http.StatusOK)
+	return nil
+}
+
+// maxDuration returns requested duration in milliseconds capped by config key
+func maxDuration(requested int, key string) time.Duration {
+	duration := time.Duration(requested) * time.Millisecond
+	if max := time.Duration(viper.GetInt(key)) * time.Millisecond; max > 0 && (duration == 0 || duration > max) {
+		duration = max
+	}
+	return duration
+}
+
+// ProblemSummary is DTO for problem in list of problems
+type ProblemSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// ProblemResponse is DTO for problem details with its visible tests
+type ProblemResponse struct {
+	*problem.Problem
+	Examples []*problem.Test `json:"examples"`
+}
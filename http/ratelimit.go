@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Strovala/crackview/ratelimit"
+)
+
+// Errors of exceeded quotas
+var (
+	ErrRateLimited       = errors.New("Too many requests")
+	ErrTooManyExecutions = errors.New("Too many concurrent executions")
+)
+
+// clientIP returns IP address of request peer
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientKey identifies client by its API key or token, or by its IP when authentication is disabled
+func clientKey(r *http.Request) string {
+	if claims := requestClaims(r); claims != nil {
+		return "key:" + claims.Role + ":" + claims.Subject + ":" + claims.SessionID
+	}
+	return "ip:" + clientIP(r)
+}
+
+func tooManyRequests(w http.ResponseWriter, err error, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	JSONErrorResponse(w, err.Error(), http.StatusTooManyRequests)
+}
+
+// RateLimit middleware takes token from limiter bucket of client identified by key function
+func RateLimit(limiter *ratelimit.Limiter, key func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if ok, retryAfter := limiter.Allow(key(r)); !ok {
+				tooManyRequests(w, ErrRateLimited, retryAfter)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// LimitExecutions middleware caps number of executions client runs at the same time
+func LimitExecutions(semaphore *ratelimit.Semaphore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			release, ok := semaphore.Acquire(clientKey(r))
+			if !ok {
+				tooManyRequests(w, ErrTooManyExecutions, time.Second)
+				return
+			}
+			defer release()
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
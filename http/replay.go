@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
+	"github.com/go-chi/chi"
+)
+
+// Types of timeline entries, document changes use session message types
+const entryExecution = "execution"
+
+// Replay returns document changes and executions of session ordered by time
+func (h *sessions) Replay(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	events, err := h.store.Events(sess.ID)
+	if err != nil {
+		return err
+	}
+	executions, err := h.store.Executions(store.ExecutionFilter{SessionID: sess.ID})
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, newTimeline(events, executions), http.StatusOK)
+	return nil
+}
+
+// ReplayDocument reconstructs session document at time given by at query
+// parameter in RFC3339 format or at revision given by revision parameter,
+// without them it returns final document
+func (h *sessions) ReplayDocument(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	events, err := h.store.Events(sess.ID)
+	if err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	var doc *session.Document
+	switch {
+	case query.Get("at") != "":
+		at, err := time.Parse(time.RFC3339, query.Get("at"))
+		if err != nil {
+			return BadRequest(err)
+		}
+		doc, err = session.ReplayAt(sess.ID, events, at)
+		if err != nil {
+			return err
+		}
+	case query.Get("revision") != "":
+		revision, err := strconv.Atoi(query.Get("revision"))
+		if err != nil {
+			return BadRequest(err)
+		}
+		doc, err = session.ReplayRevision(sess.ID, events, revision)
+		if err != nil {
+			return err
+		}
+	default:
+		doc, err = session.Replay(sess.ID, events, func(*session.Event) bool { return true })
+		if err != nil {
+			return err
+		}
+	}
+	JSONResponse(w, doc, http.StatusOK)
+	return nil
+}
+
+// TimelineEntry is document change or execution made during session
+type TimelineEntry struct {
+	Type      string           `json:"type"`
+	Time      time.Time        `json:"time"`
+	Event     *session.Event   `json:"event,omitempty"`
+	Execution *store.Execution `json:"execution,omitempty"`
+}
+
+// newTimeline interleaves document events with executions by time
+func newTimeline(events []*session.Event, executions []*store.Execution) []*TimelineEntry {
+	var result []*TimelineEntry
+	for _, event := range events {
+		result = append(result, &TimelineEntry{Type: event.Type, Time: event.Time, Event: event})
+	}
+	for _, e := range executions {
+		result = append(result, &TimelineEntry{Type: entryExecution, Time: e.CreatedAt, Execution: e})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
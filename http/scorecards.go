@@ -0,0 +1,138 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/Strovala/crackview/report"
+	"github.com/Strovala/crackview/session"
+	"github.com/go-chi/chi"
+)
+
+// Notes returns private interviewer notes of session
+func (h *sessions) Notes(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	data, err := h.store.Notes(sess.ID)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+// AddNote adds private interviewer note to session
+func (h *sessions) AddNote(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	var data NoteRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	note := &session.Note{
+		Time:   time.Now(),
+		Author: sess.Interviewer,
+		Text:   data.Text,
+	}
+	if err := h.store.AddNote(sess.ID, note); err != nil {
+		return err
+	}
+	JSONResponse(w, note, http.StatusCreated)
+	return nil
+}
+
+// Scorecard returns scorecard of session together with rubric it is scored by
+func (h *sessions) Scorecard(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	card, err := h.store.Scorecard(sess.ID)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, ScorecardResponse{Rubric: h.rubric, Scorecard: card}, http.StatusOK)
+	return nil
+}
+
+// SaveScorecard replaces scorecard of session
+func (h *sessions) SaveScorecard(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	var card session.Scorecard
+	if err := Unmarshal(&card, r); err != nil {
+		return err
+	}
+	if err := h.rubric.Validate(&card); err != nil {
+		return BadRequest(err)
+	}
+	card.UpdatedAt = time.Now()
+	if err := h.store.SaveScorecard(sess.ID, &card); err != nil {
+		return err
+	}
+	JSONResponse(w, ScorecardResponse{Rubric: h.rubric, Scorecard: &card}, http.StatusOK)
+	return nil
+}
+
+// Export returns scorecard, notes, final code and test results of session as single report
+func (h *sessions) Export(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	data, err := report.Build(h.store, h.bank, h.rubric, sess.ID)
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+// Report renders report of session as HTML or Markdown given by format query parameter
+func (h *sessions) Report(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	data, err := report.Build(h.store, h.bank, h.rubric, sess.ID)
+	if err != nil {
+		return err
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.FormatHTML
+	}
+	var out bytes.Buffer
+	if err := data.Render(&out, format); err != nil {
+		if err == report.ErrUnknownFormat {
+			return BadRequest(err)
+		}
+		return err
+	}
+	contentType := "text/html; charset=utf-8"
+	if format == report.FormatMarkdown {
+		contentType = "text/markdown; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// NoteRequest is DTO for adding note
+type NoteRequest struct {
+	Text string `json:"text"`
+}
+
+// ScorecardResponse is DTO for scorecard with rubric it is scored by
+type ScorecardResponse struct {
+	Rubric    session.Rubric     `json:"rubric"`
+	Scorecard *session.Scorecard `json:"scorecard"`
+}
@@ -3,24 +3,77 @@ package http
 import (
 	"net/http"
 
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/metrics"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/ratelimit"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 	chiCors "github.com/go-chi/cors"
+	"go.uber.org/zap"
 )
 
+// Options holds dependencies of application handler
+type Options struct {
+	Problems *problem.Bank
+	Store    *store.Store
+	Rubric   session.Rubric
+	// Hub holds documents of collaborative sessions, new hub is created when it is nil
+	Hub *session.Hub
+	// Auth authenticates every route except health, authentication is disabled when it is nil
+	Auth *auth.Authenticator
+	// RateLimit limits requests per API key and IP and concurrent executions per client
+	RateLimit ratelimit.Config
+	// AllowedOrigins can open collaboration websockets besides pages served by same host, * allows every origin
+	AllowedOrigins []string
+	// Logger logs requests and executions, defaults to global logger
+	Logger *zap.Logger
+}
+
 // NewCrackviewHandler creates new application handler
-func NewCrackviewHandler() http.Handler {
+func NewCrackviewHandler(opts Options) http.Handler {
 	cors := chiCors.New(chiCors.Options{
 		// AllowedOrigins: []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins: []string{"*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", SessionTokenHeader, APIKeyHeader},
 	})
 
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+
 	api := chi.NewMux()
+	api.Use(middleware.RequestID)
+	api.Use(RequestLogger(logger))
+	api.Use(Instrument)
 	api.Use(cors.Handler)
 	api.Use(JSONRecoverer)
 
-	api.Mount("/", newCodeHandler())
+	hub := opts.Hub
+	if hub == nil {
+		hub = session.NewHub(opts.Store)
+	}
+	executions := ratelimit.NewSemaphore(opts.RateLimit.Concurrency)
+
+	api.Get("/healthz", Health)
+	api.Get("/readyz", Ready)
+	api.Group(func(r chi.Router) {
+		r.Use(RateLimit(ratelimit.NewLimiter(opts.RateLimit.IP), clientIP))
+		if opts.Auth != nil {
+			r.Use(Authenticate(opts.Auth))
+			r.Use(RateLimit(ratelimit.NewLimiter(opts.RateLimit.Key), clientKey))
+			r.Mount("/auth", newAuthHandler(opts.Auth))
+		}
+		r.With(RequireRole(auth.RoleAdmin)).Method(http.MethodGet, "/metrics", metrics.Handler())
+		r.Mount("/", newCodeHandler(opts.Store, hub, executions))
+		r.Mount("/problems", newProblemsHandler(opts.Problems, opts.Store, hub, executions))
+		r.Mount("/executions", newExecutionsHandler(opts.Store))
+		r.Mount("/sessions", newSessionsHandler(opts.Problems, opts.Store, hub, opts.Rubric, opts.AllowedOrigins))
+	})
 	return api
 }
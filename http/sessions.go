@@ -0,0 +1,248 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Strovala/crackview/auth"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
+)
+
+// SessionTokenHeader is header with session join token
+const SessionTokenHeader = "X-Session-Token"
+
+func newSessionsHandler(bank *problem.Bank, s *store.Store, hub *session.Hub, rubric session.Rubric, origins []string) http.Handler {
+	h := sessions{bank: bank, store: s, hub: hub, rubric: rubric, upgrader: newUpgrader(origins)}
+	mux := chi.NewMux()
+	staff := mux.With(RequireRole(auth.RoleAdmin, auth.RoleInterviewer))
+	staff.Post("/", errorHandler(h.Create))
+	staff.Get("/", errorHandler(h.List))
+	mux.Get("/{id}", errorHandler(h.Get))
+	mux.Post("/{id}/join", errorHandler(h.Get))
+	mux.Post("/{id}/start", errorHandler(h.Start))
+	mux.Post("/{id}/end", errorHandler(h.End))
+	mux.Get("/{id}/executions", errorHandler(h.Executions))
+	mux.Get("/{id}/ws", errorHandler(h.Collaborate))
+	mux.Get("/{id}/replay", errorHandler(h.Replay))
+	mux.Get("/{id}/replay/document", errorHandler(h.ReplayDocument))
+	mux.Get("/{id}/notes", errorHandler(h.Notes))
+	mux.Post("/{id}/notes", errorHandler(h.AddNote))
+	mux.Get("/{id}/scorecard", errorHandler(h.Scorecard))
+	mux.Put("/{id}/scorecard", errorHandler(h.SaveScorecard))
+	mux.Get("/{id}/export", errorHandler(h.Export))
+	mux.Get("/{id}/report", errorHandler(h.Report))
+	return mux
+}
+
+type sessions struct {
+	bank   *problem.Bank
+	store  *store.Store
+	hub    *session.Hub
+	rubric session.Rubric
+	// upgrader accepts collaboration connections
+	upgrader *websocket.Upgrader
+}
+
+// sessionToken returns session token from header or token query parameter
+func sessionToken(r *http.Request) string {
+	if token := r.Header.Get(SessionTokenHeader); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// sessionError wraps session errors with matching HTTP status codes
+func sessionError(err error) error {
+	switch err {
+	case store.ErrNotFound:
+		return NotFound(err)
+	case session.ErrInvalidToken:
+		return &StatusError{Code: http.StatusUnauthorized, Err: err}
+	case session.ErrForbidden:
+		return &StatusError{Code: http.StatusForbidden, Err: err}
+	case session.ErrNotActive, session.ErrAlreadyStarted:
+		return &StatusError{Code: http.StatusConflict, Err: err}
+	case session.ErrProblemNotInSet, session.ErrNegativeLimit:
+		return BadRequest(err)
+	}
+	return err
+}
+
+// authorize loads session and returns role of request token holder,
+// role granted by signed token takes precedence over session join token
+func authorize(s *store.Store, r *http.Request, id string) (*session.Session, string, error) {
+	sess, err := s.Session(id)
+	if err != nil {
+		return nil, "", sessionError(err)
+	}
+	if role := sessionRole(r, id); role != "" {
+		return sess, role, nil
+	}
+	role, err := sess.Role(sessionToken(r))
+	if err != nil {
+		return nil, "", sessionError(err)
+	}
+	return sess, role, nil
+}
+
+// authorizeInterviewer loads session and checks that request token belongs to interviewer
+func authorizeInterviewer(s *store.Store, r *http.Request, id string) (*session.Session, error) {
+	sess, role, err := authorize(s, r, id)
+	if err != nil {
+		return nil, err
+	}
+	if role != session.RoleInterviewer {
+		return nil, sessionError(session.ErrForbidden)
+	}
+	return sess, nil
+}
+
+// attach checks that code request can be attached to its session and fills
+// candidate from session, request without code runs shared session document.
+// Requests without session are left as they are
+func attach(s *store.Store, hub *session.Hub, r *http.Request, data *CodeRequest, problemID string) error {
+	if data.SessionID == "" {
+		return nil
+	}
+	sess, role, err := authorize(s, r, data.SessionID)
+	if err != nil {
+		return err
+	}
+	if sess.State(time.Now()) != session.StateActive {
+		return sessionError(session.ErrNotActive)
+	}
+	if problemID != "" && !sess.HasProblem(problemID) {
+		return sessionError(session.ErrProblemNotInSet)
+	}
+	if role == session.RoleCandidate {
+		data.CandidateID = sess.Candidate
+	}
+	if data.Text == "" {
+		doc, err := hub.Document(sess.ID)
+		if err != nil {
+			return err
+		}
+		data.Text = doc.Text
+		if data.Lang == "" {
+			data.Lang = doc.Lang
+		}
+	}
+	return nil
+}
+
+func (h *sessions) Create(w http.ResponseWriter, r *http.Request) error {
+	var data SessionRequest
+	if err := Unmarshal(&data, r); err != nil {
+		return err
+	}
+	for _, id := range data.ProblemIDs {
+		if _, err := h.bank.Get(id); err != nil {
+			return BadRequest(err)
+		}
+	}
+	sess, err := session.New(data.Title, data.Interviewer, data.Candidate, data.ProblemIDs, data.TimeLimit)
+	if err != nil {
+		return sessionError(err)
+	}
+	if err := h.store.SaveSession(sess); err != nil {
+		return err
+	}
+	JSONResponse(w, newSessionResponse(sess, session.RoleInterviewer), http.StatusCreated)
+	return nil
+}
+
+func (h *sessions) List(w http.ResponseWriter, r *http.Request) error {
+	found, err := h.store.Sessions()
+	if err != nil {
+		return err
+	}
+	var data []*SessionResponse
+	for _, sess := range found {
+		data = append(data, newSessionResponse(sess, ""))
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+// Get returns session as seen by token holder, it is also used for joining session
+func (h *sessions) Get(w http.ResponseWriter, r *http.Request) error {
+	sess, role, err := authorize(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, newSessionResponse(sess, role), http.StatusOK)
+	return nil
+}
+
+// update applies update to session if request token belongs to interviewer
+func (h *sessions) update(w http.ResponseWriter, r *http.Request, update func(sess *session.Session) error) error {
+	id := chi.URLParam(r, "id")
+	if _, err := authorizeInterviewer(h.store, r, id); err != nil {
+		return err
+	}
+	sess, err := h.store.UpdateSession(id, update)
+	if err != nil {
+		return sessionError(err)
+	}
+	JSONResponse(w, newSessionResponse(sess, session.RoleInterviewer), http.StatusOK)
+	return nil
+}
+
+func (h *sessions) Start(w http.ResponseWriter, r *http.Request) error {
+	return h.update(w, r, func(sess *session.Session) error {
+		return sess.Start(time.Now())
+	})
+}
+
+func (h *sessions) End(w http.ResponseWriter, r *http.Request) error {
+	return h.update(w, r, func(sess *session.Session) error {
+		return sess.End(time.Now())
+	})
+}
+
+// Executions returns every execution made in session
+func (h *sessions) Executions(w http.ResponseWriter, r *http.Request) error {
+	sess, err := authorizeInterviewer(h.store, r, chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+	data, err := h.store.Executions(store.ExecutionFilter{SessionID: sess.ID})
+	if err != nil {
+		return err
+	}
+	JSONResponse(w, data, http.StatusOK)
+	return nil
+}
+
+// SessionRequest is DTO for creating session, time limit is in minutes
+type SessionRequest struct {
+	Title       string   `json:"title"`
+	ProblemIDs  []string `json:"problemIds"`
+	Interviewer string   `json:"interviewer"`
+	Candidate   string   `json:"candidate"`
+	TimeLimit   int      `json:"timeLimit"`
+}
+
+// SessionResponse is DTO for session as seen by participant with role
+type SessionResponse struct {
+	*session.Session
+	Role     string     `json:"role,omitempty"`
+	State    string     `json:"state"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+func newSessionResponse(sess *session.Session, role string) *SessionResponse {
+	resp := &SessionResponse{
+		Session: sess.ForRole(role),
+		Role:    role,
+		State:   sess.State(time.Now()),
+	}
+	if deadline := sess.Deadline(); !deadline.IsZero() {
+		resp.Deadline = &deadline
+	}
+	return resp
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/session"
+)
+
+// request sends request with JSON body and session token, decodes data of
+// response into dest and returns status code
+func request(t *testing.T, method, url, token string, body, dest interface{}) int {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set(SessionTokenHeader, token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if dest != nil && resp.StatusCode < http.StatusBadRequest {
+		if err := json.NewDecoder(resp.Body).Decode(&struct{ Data interface{} }{Data: dest}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func newSessionsServer(t *testing.T) *httptest.Server {
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewCrackviewHandler(Options{Problems: bank, Store: newTestStore(t)}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCreateSession(t *testing.T) {
+	server := newSessionsServer(t)
+	tests := []struct {
+		name string
+		data SessionRequest
+		want int
+	}{
+		{name: "valid", data: SessionRequest{Title: "Screen", ProblemIDs: []string{"two-sum"}, TimeLimit: 45}, want: http.StatusCreated},
+		{name: "without time limit", data: SessionRequest{Title: "Screen"}, want: http.StatusCreated},
+		{name: "negative time limit", data: SessionRequest{Title: "Screen", TimeLimit: -5}, want: http.StatusBadRequest},
+		{name: "unknown problem", data: SessionRequest{Title: "Screen", ProblemIDs: []string{"missing"}}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created SessionResponse
+			if status := request(t, http.MethodPost, server.URL+"/sessions", "", tt.data, &created); status != tt.want {
+				t.Fatalf("status = %v, want %v", status, tt.want)
+			}
+			if tt.want == http.StatusCreated && (created.InterviewerToken == "" || created.CandidateToken == "" || created.State != session.StatePending) {
+				t.Errorf("created = %+v, want pending session with tokens", created)
+			}
+		})
+	}
+}
+
+func TestJoinSession(t *testing.T) {
+	server := newSessionsServer(t)
+	var created SessionResponse
+	if status := request(t, http.MethodPost, server.URL+"/sessions", "", SessionRequest{Title: "Screen", TimeLimit: 45}, &created); status != http.StatusCreated {
+		t.Fatalf("create status = %v", status)
+	}
+	url := server.URL + "/sessions/" + created.ID
+
+	var joined SessionResponse
+	if status := request(t, http.MethodPost, url+"/join", created.CandidateToken, nil, &joined); status != http.StatusOK {
+		t.Fatalf("join status = %v, want %v", status, http.StatusOK)
+	}
+	if joined.Role != session.RoleCandidate || joined.InterviewerToken != "" || joined.CandidateToken != "" {
+		t.Errorf("candidate sees %+v, want candidate role without tokens", joined)
+	}
+	for name, token := range map[string]string{"without token": "", "wrong token": "nope"} {
+		if status := request(t, http.MethodPost, url+"/join", token, nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("join %v status = %v, want %v", name, status, http.StatusUnauthorized)
+		}
+	}
+	if status := request(t, http.MethodGet, server.URL+"/sessions/missing", created.CandidateToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("get of missing session status = %v, want %v", status, http.StatusNotFound)
+	}
+
+	if status := request(t, http.MethodPost, url+"/start", created.CandidateToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("start by candidate status = %v, want %v", status, http.StatusForbidden)
+	}
+	var started SessionResponse
+	if status := request(t, http.MethodPost, url+"/start", created.InterviewerToken, nil, &started); status != http.StatusOK {
+		t.Fatalf("start status = %v, want %v", status, http.StatusOK)
+	}
+	if started.State != session.StateActive || started.Deadline == nil {
+		t.Errorf("started = %+v, want active session with deadline", started)
+	}
+	if status := request(t, http.MethodPost, url+"/start", created.InterviewerToken, nil, nil); status != http.StatusConflict {
+		t.Errorf("second start status = %v, want %v", status, http.StatusConflict)
+	}
+	var ended SessionResponse
+	if status := request(t, http.MethodPost, url+"/end", created.InterviewerToken, nil, &ended); status != http.StatusOK || ended.State != session.StateEnded {
+		t.Errorf("end = %v, %v, want %v, %v", status, ended.State, http.StatusOK, session.StateEnded)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Strovala/crackview/logging"
+	"go.uber.org/zap"
+)
+
+// NoWriteTimeout clears write deadline of server for routes which run code,
+// streamed output and runs of many tests can take longer than server write
+// timeout. Runs are limited by time limits and maximal durations from config
+func NoWriteTimeout(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			logging.FromContext(r.Context()).Warn("unable to clear write deadline", zap.Error(err))
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
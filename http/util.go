@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"os"
 	"runtime/debug"
+
+	"github.com/Strovala/crackview/logging"
+	"go.uber.org/zap"
 )
 
 type handlerWithError func(http.ResponseWriter, *http.Request) error
@@ -18,10 +20,37 @@ var (
 	ErrParseJSONBodyType = errors.New("Cannot unmarshal JSON body into type")
 )
 
+// StatusError is error which is written to response with its HTTP status code
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// NotFound wraps error so it is written to response with 404 status code
+func NotFound(err error) error {
+	return &StatusError{Code: http.StatusNotFound, Err: err}
+}
+
+// BadRequest wraps error so it is written to response with 400 status code
+func BadRequest(err error) error {
+	return &StatusError{Code: http.StatusBadRequest, Err: err}
+}
+
 func errorHandler(next handlerWithError) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := next(w, r); err != nil {
-			JSONErrorResponse(w, err.Error(), 500)
+			code := http.StatusInternalServerError
+			if statusErr, ok := err.(*StatusError); ok {
+				code = statusErr.Code
+			}
+			if code == http.StatusInternalServerError {
+				logging.FromContext(r.Context()).Error("unable to serve request", zap.Error(err))
+			}
+			JSONErrorResponse(w, err.Error(), code)
 		}
 	}
 }
@@ -84,8 +113,10 @@ func JSONRecoverer(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if rvr := recover(); rvr != nil {
-				fmt.Fprintf(os.Stderr, "Panic: %+v\n", rvr)
-				debug.PrintStack()
+				logging.FromContext(r.Context()).Error("recovered from panic",
+					zap.Any("panic", rvr),
+					zap.ByteString("stack", debug.Stack()),
+				)
 				JSONErrorResponse(w, "Unexpected error occurred", http.StatusInternalServerError)
 				return
 			}
@@ -94,3 +125,36 @@ func JSONRecoverer(next http.Handler) http.Handler {
 	}
 	return http.HandlerFunc(fn)
 }
+
+// ErrStreamingUnsupported is returned when response writer can not be flushed
+var ErrStreamingUnsupported = errors.New("Streaming is not supported")
+
+// EventStream writes server sent events
+type EventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewEventStream writes server sent events headers and returns stream
+func NewEventStream(w http.ResponseWriter) (*EventStream, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &EventStream{w: w, flusher: flusher}, nil
+}
+
+// Send writes event with JSON serialized data and flushes it to the client
+func (s *EventStream) Send(event string, data interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(s.w, "event: %v\ndata: %v\n\n", event, string(b))
+	s.flusher.Flush()
+}
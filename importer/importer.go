@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Strovala/crackview/parser"
+	"github.com/Strovala/crackview/problem"
+	pkgErrors "github.com/pkg/errors"
+)
+
+// Formats problems can be imported from
+const (
+	FormatPolygon = "polygon"
+	FormatJSON    = "json"
+)
+
+// ErrUnknownFormat is returned when format of problem package is not supported or can not be detected
+var ErrUnknownFormat = errors.New("Unknown problem format")
+
+// Options of import, Examples is number of first tests which are shown to
+// candidate when format does not mark them
+type Options struct {
+	Format   string
+	ID       string
+	Examples int
+}
+
+// rawTest is test read from imported format before its types are inferred
+type rawTest struct {
+	name     string
+	args     []*node
+	expected *node
+	hidden   bool
+}
+
+// Import converts problem package at path to problem, format is detected when it is not set
+func Import(path string, opts Options) (*problem.Problem, error) {
+	format := opts.Format
+	if format == "" {
+		format = Detect(path)
+	}
+	var p *problem.Problem
+	var err error
+	switch format {
+	case FormatPolygon:
+		p, err = importPolygon(path, opts)
+	case FormatJSON:
+		p, err = importJSON(path)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrapf(err, "import %v", path)
+	}
+	if opts.ID != "" {
+		p.ID = opts.ID
+	}
+	if p.ID == "" {
+		p.ID = problemID(path)
+	}
+	if p.Title == "" {
+		p.Title = p.ID
+	}
+	return p, nil
+}
+
+// Detect detects format of problem package, json files are in json format and
+// directories with tests directory are in Polygon format
+func Detect(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if !info.IsDir() {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return FormatJSON
+		}
+		return ""
+	}
+	if tests, err := os.Stat(filepath.Join(path, polygonTestsDir)); err == nil && tests.IsDir() {
+		return FormatPolygon
+	}
+	return ""
+}
+
+// problemID creates problem id from file or directory name
+func problemID(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-")
+}
+
+// build infers types of tests which are not declared by signature and
+// converts tests to parser syntax
+func build(signature problem.Signature, tests []*rawTest) (problem.Signature, []*problem.Test, error) {
+	if len(tests) == 0 {
+		return signature, nil, errors.New("package has no tests")
+	}
+	argCount := len(tests[0].args)
+	for _, test := range tests {
+		if len(test.args) != argCount {
+			return signature, nil, fmt.Errorf("test %v has %v arguments, expected %v", test.name, len(test.args), argCount)
+		}
+	}
+	if len(signature.Args) != 0 && len(signature.Args) != argCount {
+		return signature, nil, fmt.Errorf("signature has %v arguments, tests have %v", len(signature.Args), argCount)
+	}
+
+	argSpecs := make([]spec, argCount)
+	for i := range argSpecs {
+		if len(signature.Args) != 0 {
+			argSpecs[i] = parseSpec(signature.Args[i])
+			continue
+		}
+		values := make([]*node, len(tests))
+		for j, test := range tests {
+			values[j] = test.args[i]
+		}
+		s, err := inferAll(values)
+		if err != nil {
+			return signature, nil, pkgErrors.Wrapf(err, "argument %v", i+1)
+		}
+		argSpecs[i] = s
+	}
+	returnsSpec := parseSpec(signature.Returns)
+	if signature.Returns == "" {
+		values := make([]*node, len(tests))
+		for j, test := range tests {
+			values[j] = test.expected
+		}
+		s, err := inferAll(values)
+		if err != nil {
+			return signature, nil, pkgErrors.Wrap(err, "expected output")
+		}
+		returnsSpec = s
+	}
+
+	result := problem.Signature{Returns: returnsSpec.String()}
+	for _, s := range argSpecs {
+		result.Args = append(result.Args, s.String())
+	}
+	var converted []*problem.Test
+	for _, test := range tests {
+		lines := make([]string, argCount)
+		for i, arg := range test.args {
+			lines[i] = format(arg, argSpecs[i]) + " => " + result.Args[i]
+		}
+		t := &problem.Test{
+			Name:     test.name,
+			Input:    strings.Join(lines, "\n"),
+			Expected: format(test.expected, returnsSpec),
+			Hidden:   test.hidden,
+		}
+		if _, err := parser.Parse(t.Input); err != nil {
+			return signature, nil, pkgErrors.Wrapf(err, "test %v", test.name)
+		}
+		if _, err := parser.ParseValue(t.Expected, result.Returns); err != nil {
+			return signature, nil, pkgErrors.Wrapf(err, "test %v", test.name)
+		}
+		converted = append(converted, t)
+	}
+	return result, converted, nil
+}
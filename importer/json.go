@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/Strovala/crackview/problem"
+)
+
+// jsonTest is test in json format, Input holds arguments in order
+type jsonTest struct {
+	Name   string        `json:"name"`
+	Input  []interface{} `json:"input"`
+	Output interface{}   `json:"output"`
+}
+
+// jsonProblem is problem in json format, examples are shown to candidate and
+// tests are hidden. Types of signature are inferred from values when it is omitted
+type jsonProblem struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	Statement string            `json:"statement"`
+	TimeLimit int               `json:"timeLimit"`
+	Signature problem.Signature `json:"signature"`
+	Examples  []jsonTest        `json:"examples"`
+	Tests     []jsonTest        `json:"tests"`
+}
+
+// importJSON imports problem from json file
+func importJSON(path string) (*problem.Problem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	data := &jsonProblem{}
+	if err := decoder.Decode(data); err != nil {
+		return nil, err
+	}
+
+	var tests []*rawTest
+	add := func(jsonTests []jsonTest, hidden bool) error {
+		for _, t := range jsonTests {
+			name := t.Name
+			if name == "" {
+				name = strconv.Itoa(len(tests) + 1)
+			}
+			test := &rawTest{name: name, hidden: hidden}
+			for _, arg := range t.Input {
+				n, err := jsonNode(arg)
+				if err != nil {
+					return fmt.Errorf("test %v: %v", name, err)
+				}
+				test.args = append(test.args, n)
+			}
+			if test.expected, err = jsonNode(t.Output); err != nil {
+				return fmt.Errorf("test %v: %v", name, err)
+			}
+			tests = append(tests, test)
+		}
+		return nil
+	}
+	if err := add(data.Examples, false); err != nil {
+		return nil, err
+	}
+	if err := add(data.Tests, true); err != nil {
+		return nil, err
+	}
+
+	signature, converted, err := build(data.Signature, tests)
+	if err != nil {
+		return nil, err
+	}
+	return &problem.Problem{
+		ID:        data.ID,
+		Title:     data.Title,
+		Signature: signature,
+		TimeLimit: data.TimeLimit,
+		Statement: data.Statement,
+		Tests:     converted,
+	}, nil
+}
+
+// jsonNode converts decoded json value to node, map keys are sorted
+func jsonNode(value interface{}) (*node, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return token(v.String()), nil
+	case bool:
+		return leaf(strconv.FormatBool(v), kindBool), nil
+	case string:
+		return leaf(v, kindString), nil
+	case []interface{}:
+		n := &node{isList: true}
+		for _, item := range v {
+			child, err := jsonNode(item)
+			if err != nil {
+				return nil, err
+			}
+			n.list = append(n.list, child)
+		}
+		return n, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		n := &node{isMap: true}
+		for _, key := range keys {
+			child, err := jsonNode(v[key])
+			if err != nil {
+				return nil, err
+			}
+			n.keys = append(n.keys, token(key))
+			n.values = append(n.values, child)
+		}
+		return n, nil
+	}
+	return nil, ErrUnsupportedValue
+}
@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Strovala/crackview/problem"
+)
+
+// Files of Polygon style package
+const (
+	polygonTestsDir      = "tests"
+	polygonDescriptor    = "problem.xml"
+	polygonStatementsDir = "statements"
+	polygonMarkdown      = "statement.md"
+)
+
+// polygonInputExts and polygonAnswerExts are extensions of test input and answer
+// files, Polygon names inputs without extension and answers with .a
+var (
+	polygonInputExts  = []string{".in", ""}
+	polygonAnswerExts = []string{".ans", ".a", ".out"}
+)
+
+// polygonStatementParts are statement sections in order they are joined
+var polygonStatementParts = []struct {
+	file    string
+	heading string
+}{
+	{"legend.tex", ""},
+	{"input.tex", "Input"},
+	{"output.tex", "Output"},
+	{"notes.tex", "Notes"},
+}
+
+// defaultExamples is number of first tests shown to candidate when package does not mark samples
+const defaultExamples = 1
+
+type polygonName struct {
+	Language string `xml:"language,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type polygonTest struct {
+	Sample bool `xml:"sample,attr"`
+}
+
+type polygonTestset struct {
+	Name      string        `xml:"name,attr"`
+	TimeLimit int           `xml:"time-limit"`
+	Tests     []polygonTest `xml:"tests>test"`
+}
+
+// polygonProblem is part of problem.xml descriptor which is imported
+type polygonProblem struct {
+	ShortName string           `xml:"short-name,attr"`
+	Names     []polygonName    `xml:"names>name"`
+	Testsets  []polygonTestset `xml:"judging>testset"`
+}
+
+func (p *polygonProblem) title() string {
+	for _, name := range p.Names {
+		if name.Language == "english" {
+			return name.Value
+		}
+	}
+	if len(p.Names) > 0 {
+		return p.Names[0].Value
+	}
+	return ""
+}
+
+func (p *polygonProblem) testset() *polygonTestset {
+	for i := range p.Testsets {
+		if p.Testsets[i].Name == "tests" {
+			return &p.Testsets[i]
+		}
+	}
+	if len(p.Testsets) > 0 {
+		return &p.Testsets[0]
+	}
+	return &polygonTestset{}
+}
+
+// importPolygon imports directory with tests/*.in and tests/*.ans files and
+// optional problem.xml descriptor and statements. Every line of input is an
+// argument, line with single number followed by line with that many values is
+// treated as length of array
+func importPolygon(dir string, opts Options) (*problem.Problem, error) {
+	descriptor := &polygonProblem{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, polygonDescriptor))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := xml.Unmarshal(data, descriptor); err != nil {
+			return nil, err
+		}
+	}
+	testset := descriptor.testset()
+
+	inputs, answers, names, err := readPolygonTests(filepath.Join(dir, polygonTestsDir))
+	if err != nil {
+		return nil, err
+	}
+	examples := opts.Examples
+	if examples <= 0 {
+		examples = defaultExamples
+	}
+	tests, err := polygonTests(inputs, answers, names, true)
+	if err != nil {
+		// counted arrays are guessed differently across tests, read every line as it is
+		if tests, err = polygonTests(inputs, answers, names, false); err != nil {
+			return nil, err
+		}
+	}
+	for i, test := range tests {
+		test.hidden = i >= examples
+		if len(testset.Tests) == len(tests) {
+			test.hidden = !testset.Tests[i].Sample
+		}
+	}
+
+	signature, converted, err := build(problem.Signature{}, tests)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := polygonStatement(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &problem.Problem{
+		ID:        descriptor.ShortName,
+		Title:     descriptor.title(),
+		Signature: signature,
+		TimeLimit: testset.TimeLimit,
+		Statement: statement,
+		Tests:     converted,
+	}, nil
+}
+
+// readPolygonTests reads contents of test inputs and answers in natural order
+func readPolygonTests(dir string) (inputs, answers, names []string, err error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	exists := map[string]bool{}
+	for _, file := range files {
+		exists[file.Name()] = true
+	}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || !isInputExt(ext) {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ext)
+		for _, answerExt := range polygonAnswerExts {
+			if exists[name+answerExt] {
+				names = append(names, file.Name())
+				break
+			}
+		}
+	}
+	problem.NaturalSort(names)
+	for _, fileName := range names {
+		name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		input, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var answer []byte
+		for _, answerExt := range polygonAnswerExts {
+			if exists[name+answerExt] {
+				if answer, err = ioutil.ReadFile(filepath.Join(dir, name+answerExt)); err != nil {
+					return nil, nil, nil, err
+				}
+				break
+			}
+		}
+		inputs = append(inputs, string(input))
+		answers = append(answers, string(answer))
+	}
+	for i := range names {
+		names[i] = strings.TrimSuffix(names[i], filepath.Ext(names[i]))
+	}
+	return inputs, answers, names, nil
+}
+
+func isInputExt(ext string) bool {
+	for _, inputExt := range polygonInputExts {
+		if ext == inputExt {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonTests reads arguments of every test, it fails when tests have
+// different number of arguments
+func polygonTests(inputs, answers, names []string, counted bool) ([]*rawTest, error) {
+	var tests []*rawTest
+	for i := range inputs {
+		test := &rawTest{
+			name:     names[i],
+			args:     readValues(inputs[i], counted),
+			expected: readAnswer(answers[i]),
+		}
+		if len(tests) > 0 && len(test.args) != len(tests[0].args) {
+			return nil, fmt.Errorf("test %v has different number of arguments than other tests", test.name)
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+// readValues reads every non empty line as value, line with many tokens is list
+func readValues(text string, counted bool) []*node {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	var values []*node
+	for i := 0; i < len(lines); i++ {
+		tokens := strings.Fields(lines[i])
+		if counted && len(tokens) == 1 && i+1 < len(lines) {
+			if n, err := strconv.Atoi(tokens[0]); err == nil && n >= 0 {
+				next := strings.Fields(lines[i+1])
+				if len(next) == n {
+					values = append(values, list(next))
+					i++
+					continue
+				}
+			}
+		}
+		switch len(tokens) {
+		case 0:
+		case 1:
+			values = append(values, token(tokens[0]))
+		default:
+			values = append(values, list(tokens))
+		}
+	}
+	return values
+}
+
+// readAnswer reads answer as single value, answer spanning many values is list
+func readAnswer(text string) *node {
+	values := readValues(text, true)
+	if len(values) == 1 {
+		return values[0]
+	}
+	return list(strings.Fields(text))
+}
+
+func list(tokens []string) *node {
+	n := &node{isList: true}
+	for _, t := range tokens {
+		n.list = append(n.list, token(t))
+	}
+	return n
+}
+
+// polygonStatement reads statement.md or joins tex sections of english statement
+func polygonStatement(dir string) (string, error) {
+	markdown, err := ioutil.ReadFile(filepath.Join(dir, polygonMarkdown))
+	if err == nil {
+		return string(markdown), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	statementDir := filepath.Join(dir, polygonStatementsDir, "english")
+	if _, err := os.Stat(statementDir); os.IsNotExist(err) {
+		languages, _ := ioutil.ReadDir(filepath.Join(dir, polygonStatementsDir))
+		if len(languages) == 0 {
+			return "", nil
+		}
+		statementDir = filepath.Join(dir, polygonStatementsDir, languages[0].Name())
+	}
+	var sections []string
+	for _, part := range polygonStatementParts {
+		content, err := ioutil.ReadFile(filepath.Join(statementDir, part.file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		section := strings.TrimSpace(string(content))
+		if part.heading != "" {
+			section = "## " + part.heading + "\n\n" + section
+		}
+		sections = append(sections, section)
+	}
+	if len(sections) == 0 {
+		return "", nil
+	}
+	return strings.Join(sections, "\n\n") + "\n", nil
+}
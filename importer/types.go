@@ -0,0 +1,211 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of simple values in parser syntax
+const (
+	kindInt    = "int"
+	kindFloat  = "float"
+	kindBool   = "bool"
+	kindString = "string"
+)
+
+// Containers of values in parser syntax
+const (
+	containerArray = "[]"
+	containerSet   = "()"
+	containerMap   = "{}"
+)
+
+// ErrUnsupportedValue is returned for values parser can not express, like nested lists
+var ErrUnsupportedValue = errors.New("Value can not be expressed in parser syntax")
+
+// node is value read from imported format, it is either simple value with raw
+// text, list of simple values or map from simple values to simple values
+type node struct {
+	kind   string
+	raw    string
+	list   []*node
+	isList bool
+	keys   []*node
+	values []*node
+	isMap  bool
+}
+
+func leaf(raw, kind string) *node {
+	return &node{raw: raw, kind: kind}
+}
+
+// token creates simple value from text token, its kind is inferred from text
+func token(raw string) *node {
+	return leaf(raw, kindOf(raw))
+}
+
+func kindOf(raw string) string {
+	if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return kindInt
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return kindFloat
+	}
+	if raw == "true" || raw == "false" {
+		return kindBool
+	}
+	return kindString
+}
+
+// spec is type of value in parser syntax, Elem is type of simple value or
+// type of elements and Key is type of map keys. Empty Elem means that type is
+// unknown because every list was empty
+type spec struct {
+	Container string
+	Key       string
+	Elem      string
+}
+
+func (s spec) String() string {
+	elem := s.Elem
+	if elem == "" {
+		elem = kindInt
+	}
+	if s.Container == containerMap {
+		key := s.Key
+		if key == "" {
+			key = kindString
+		}
+		return s.Container + key + "," + elem
+	}
+	return s.Container + elem
+}
+
+// parseSpec parses type in parser syntax
+func parseSpec(valType string) spec {
+	valType = strings.TrimSpace(valType)
+	for _, container := range []string{containerArray, containerSet, containerMap} {
+		if !strings.HasPrefix(valType, container) {
+			continue
+		}
+		rest := valType[len(container):]
+		if container == containerMap {
+			parts := strings.SplitN(rest, ",", 2)
+			if len(parts) == 2 {
+				return spec{Container: container, Key: strings.TrimSpace(parts[0]), Elem: strings.TrimSpace(parts[1])}
+			}
+		}
+		return spec{Container: container, Elem: rest}
+	}
+	return spec{Elem: valType}
+}
+
+// widen returns narrowest kind both kinds can be expressed with
+func widen(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "", a == b:
+		return a
+	case (a == kindInt && b == kindFloat) || (a == kindFloat && b == kindInt):
+		return kindFloat
+	}
+	return kindString
+}
+
+// infer infers type of value
+func infer(n *node) (spec, error) {
+	switch {
+	case n.isList:
+		s := spec{Container: containerArray}
+		for _, item := range n.list {
+			if item.isList || item.isMap {
+				return s, ErrUnsupportedValue
+			}
+			s.Elem = widen(s.Elem, item.kind)
+		}
+		return s, nil
+	case n.isMap:
+		s := spec{Container: containerMap}
+		for i := range n.keys {
+			if n.values[i].isList || n.values[i].isMap {
+				return s, ErrUnsupportedValue
+			}
+			s.Key = widen(s.Key, n.keys[i].kind)
+			s.Elem = widen(s.Elem, n.values[i].kind)
+		}
+		return s, nil
+	}
+	return spec{Elem: n.kind}, nil
+}
+
+// unify returns type both types can be expressed with, simple value can be
+// expressed as array with single element
+func unify(a, b spec) (spec, error) {
+	if a.Container != b.Container {
+		switch {
+		case a.Container == "" && b.Container == containerArray:
+			a.Container = containerArray
+		case a.Container == containerArray && b.Container == "":
+			b.Container = containerArray
+		default:
+			return a, fmt.Errorf("values of types %v and %v can not be unified", a, b)
+		}
+	}
+	return spec{Container: a.Container, Key: widen(a.Key, b.Key), Elem: widen(a.Elem, b.Elem)}, nil
+}
+
+// inferAll infers type every value can be expressed with
+func inferAll(nodes []*node) (spec, error) {
+	var result spec
+	for i, n := range nodes {
+		s, err := infer(n)
+		if err != nil {
+			return result, err
+		}
+		if i == 0 {
+			result = s
+			continue
+		}
+		if result, err = unify(result, s); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// format formats value in parser syntax as value of type
+func format(n *node, s spec) string {
+	switch s.Container {
+	case containerArray, containerSet:
+		items := n.list
+		if !n.isList {
+			items = []*node{n}
+		}
+		formatted := make([]string, len(items))
+		for i, item := range items {
+			formatted[i] = formatSimple(item, s.Elem)
+		}
+		open, close := "[", "]"
+		if s.Container == containerSet {
+			open, close = "(", ")"
+		}
+		return open + strings.Join(formatted, ", ") + close
+	case containerMap:
+		formatted := make([]string, len(n.keys))
+		for i := range n.keys {
+			formatted[i] = formatSimple(n.keys[i], s.Key) + ": " + formatSimple(n.values[i], s.Elem)
+		}
+		return "{" + strings.Join(formatted, ", ") + "}"
+	}
+	return formatSimple(n, s.Elem)
+}
+
+func formatSimple(n *node, kind string) string {
+	if kind == kindString {
+		return strconv.Quote(n.raw)
+	}
+	return n.raw
+}
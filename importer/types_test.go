@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// jsonNodes decodes every value as imported json package does
+func jsonNodes(t *testing.T, values []string) []*node {
+	var nodes []*node
+	for _, value := range values {
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.UseNumber()
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			t.Fatalf("decode %v: %v", value, err)
+		}
+		n, err := jsonNode(decoded)
+		if err != nil {
+			t.Fatalf("jsonNode(%v) error: %v", value, err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func TestInferAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    string
+		first   string
+		wantErr bool
+	}{
+		{name: "int", values: []string{"1", "-5"}, want: "int", first: "1"},
+		{name: "int widens to float", values: []string{"1", "2.5"}, want: "float", first: "1"},
+		{name: "bool", values: []string{"true", "false"}, want: "bool", first: "true"},
+		{name: "string", values: []string{`"abc"`}, want: "string", first: `"abc"`},
+		{name: "number and string widen to string", values: []string{"1", `"a"`}, want: "string", first: `"1"`},
+		{name: "array", values: []string{"[1, 2]", "[3]"}, want: "[]int", first: "[1, 2]"},
+		{name: "empty arrays default to int", values: []string{"[]", "[]"}, want: "[]int", first: "[]"},
+		{name: "empty array takes type of others", values: []string{"[]", `["x"]`}, want: "[]string", first: "[]"},
+		{name: "simple value joins arrays", values: []string{"7", "[1.5, 2]"}, want: "[]float", first: "[7]"},
+		{name: "map", values: []string{`{"a": 1, "b": 2}`}, want: "{}string,int", first: `{"a": 1, "b": 2}`},
+		{name: "map with number keys", values: []string{`{"2": true, "1": false}`}, want: "{}int,bool", first: "{1: false, 2: true}"},
+		{name: "nested list", values: []string{"[[1], [2]]"}, wantErr: true},
+		{name: "map of lists", values: []string{`{"a": [1]}`}, wantErr: true},
+		{name: "array and map", values: []string{"[1]", `{"a": 1}`}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := jsonNodes(t, tt.values)
+			got, err := inferAll(nodes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("inferAll = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("inferAll error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("inferAll = %v, want %v", got, tt.want)
+			}
+			if first := format(nodes[0], got); first != tt.first {
+				t.Errorf("format = %v, want %v", first, tt.first)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := map[string]string{
+		"42":    kindInt,
+		"-3":    kindInt,
+		"3.14":  kindFloat,
+		"1e9":   kindFloat,
+		"true":  kindBool,
+		"True":  kindString,
+		"abc":   kindString,
+		"":      kindString,
+		"1,000": kindString,
+	}
+	for raw, want := range tests {
+		if got := kindOf(raw); got != want {
+			t.Errorf("kindOf(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	for _, valType := range []string{"int", "[]float", "()string", "{}int,bool"} {
+		if got := parseSpec(valType).String(); got != valType {
+			t.Errorf("parseSpec(%q) = %v", valType, got)
+		}
+	}
+}
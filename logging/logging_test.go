@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		logged  []zapcore.Level
+		skipped []zapcore.Level
+		wantErr bool
+	}{
+		{name: "defaults", config: Config{}, logged: []zapcore.Level{zapcore.InfoLevel}, skipped: []zapcore.Level{zapcore.DebugLevel}},
+		{name: "debug", config: Config{Level: "debug", Format: FormatJSON}, logged: []zapcore.Level{zapcore.DebugLevel, zapcore.ErrorLevel}},
+		{name: "warn", config: Config{Level: "warn", Format: FormatJSON}, logged: []zapcore.Level{zapcore.WarnLevel}, skipped: []zapcore.Level{zapcore.InfoLevel}},
+		{name: "console", config: Config{Level: "error", Format: FormatConsole}, logged: []zapcore.Level{zapcore.ErrorLevel}, skipped: []zapcore.Level{zapcore.WarnLevel}},
+		{name: "unknown level", config: Config{Level: "loud"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, err := New(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New(%+v) error = %v, want error %v", tt.config, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, level := range tt.logged {
+				if !logger.Core().Enabled(level) {
+					t.Errorf("level %v is not logged", level)
+				}
+			}
+			for _, level := range tt.skipped {
+				if logger.Core().Enabled(level) {
+					t.Errorf("level %v is logged", level)
+				}
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if logger := FromContext(context.Background()); logger != zap.L() {
+		t.Errorf("FromContext() without logger = %v, want global logger", logger)
+	}
+	logger := zap.NewExample()
+	if got := FromContext(WithLogger(context.Background(), logger)); got != logger {
+		t.Errorf("FromContext() = %v, want logger put into context", got)
+	}
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crackview"
+
+// durationBuckets cover anything from quick interpreter runs to slow compilations in seconds
+var durationBuckets = []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Registry holds every crackview metric together with go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+// Metrics of executions, sessions and http server
+var (
+	Executions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "executions_total",
+		Help:      "Number of executed solutions by language and verdict.",
+	}, []string{"lang", "verdict"})
+	CompileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "compile_duration_seconds",
+		Help:      "Time spent compiling solutions by language.",
+		Buckets:   durationBuckets,
+	}, []string{"lang"})
+	RunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "run_duration_seconds",
+		Help:      "Time spent running compiled solutions by language.",
+		Buckets:   durationBuckets,
+	}, []string{"lang"})
+	QueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "execution_queue_depth",
+		Help:      "Number of executions which are being generated, compiled or run.",
+	})
+	ActiveSessions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Number of interview sessions with connected collaborators.",
+	})
+	ParseErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "parse_errors_total",
+		Help:      "Number of values and types which could not be parsed by source.",
+	}, []string{"source"})
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Sources of parse errors
+const (
+	SourceInput   = "input"
+	SourceReturns = "returns"
+	SourceResult  = "result"
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		Executions,
+		CompileDuration,
+		RunDuration,
+		QueueDepth,
+		ActiveSessions,
+		ParseErrors,
+		HTTPDuration,
+	)
+}
+
+// ObserveExecution records verdict and durations of execution, times are in milliseconds
+// and compile time is recorded only for languages which are compiled
+func ObserveExecution(lang, verdict string, compileTime, runTime int64) {
+	Executions.WithLabelValues(lang, verdict).Inc()
+	if compileTime > 0 {
+		CompileDuration.WithLabelValues(lang).Observe(seconds(compileTime))
+	}
+	if verdict != execution.VerdictCompilationError {
+		RunDuration.WithLabelValues(lang).Observe(seconds(runTime))
+	}
+}
+
+func seconds(milliseconds int64) float64 {
+	return (time.Duration(milliseconds) * time.Millisecond).Seconds()
+}
+
+// Handler serves metrics in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// scrape returns metrics served by Handler in Prometheus text format
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %v, want %v", w.Code, http.StatusOK)
+	}
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestObserveExecution(t *testing.T) {
+	tests := []struct {
+		name        string
+		lang        string
+		verdict     string
+		compileTime int64
+		runTime     int64
+		// compiled and ran are numbers of recorded compile and run durations
+		compiled int
+		ran      int
+	}{
+		{name: "compiled", lang: "compiled", verdict: execution.VerdictOK, compileTime: 1200, runTime: 30, compiled: 1, ran: 1},
+		{name: "interpreted", lang: "interpreted", verdict: execution.VerdictRuntimeError, runTime: 30, ran: 1},
+		{name: "compilation error", lang: "broken", verdict: execution.VerdictCompilationError, compileTime: 800, compiled: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ObserveExecution(tt.lang, tt.verdict, tt.compileTime, tt.runTime)
+			if count := testutil.ToFloat64(Executions.WithLabelValues(tt.lang, tt.verdict)); count != 1 {
+				t.Errorf("executions = %v, want 1", count)
+			}
+			out := scrape(t)
+			for name, want := range map[string]int{"compile": tt.compiled, "run": tt.ran} {
+				line := `crackview_` + name + `_duration_seconds_count{lang="` + tt.lang + `"} 1`
+				if got := strings.Contains(out, line); got != (want == 1) {
+					t.Errorf("%v duration recorded = %v, want %v", name, got, want == 1)
+				}
+			}
+		})
+	}
+	out := scrape(t)
+	if !strings.Contains(out, `crackview_compile_duration_seconds_sum{lang="compiled"} 1.2`) {
+		t.Errorf("compile duration is not recorded in seconds:\n%v", out)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	QueueDepth.Inc()
+	defer QueueDepth.Dec()
+	ParseErrors.WithLabelValues(SourceInput).Inc()
+	out := scrape(t)
+	for _, want := range []string{
+		"crackview_execution_queue_depth 1",
+		`crackview_parse_errors_total{source="input"} 1`,
+		"crackview_active_sessions 0",
+		"go_goroutines",
+		"process_",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+}
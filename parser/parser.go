@@ -23,6 +23,21 @@ const (
 	floatType  = "float"
 )
 
+// ErrUnknownType is returned when type of value is not supported
+var ErrUnknownType = errors.New("unknown type")
+
+// ErrMalformedEntry is returned when map entry is not key: value
+var ErrMalformedEntry = errors.New("malformed map entry")
+
+// generatorTypes maps parser types to types used by generator
+var generatorTypes = map[string]string{
+	intType:    "int",
+	boolType:   "bool",
+	stringType: "string",
+	floatType:  "float64",
+}
+
+// Parse parses input where every line is value with its type ex. [1, 3, 5] => []int
 func Parse(input string) ([]generator.Argument, error) {
 	splitedInput := strings.TrimSpace(input)
 	var result []generator.Argument
@@ -43,7 +58,53 @@ func Parse(input string) ([]generator.Argument, error) {
 	return result, nil
 }
 
+// ParseValue parses single value of given type ex. ParseValue("[1, 3, 5]", "[]int")
+func ParseValue(val string, valType string) (generator.Argument, error) {
+	return parse(val, strings.TrimSpace(valType))
+}
+
+// ParseType parses type of value returned by solution ex. ParseType("{}int,string")
+func ParseType(valType string) (*generator.Result, error) {
+	valType = strings.TrimSpace(valType)
+	if len(valType) < 2 {
+		return generatorType(valType, generator.NewSimpleResult)
+	}
+	vType := valType[2:]
+	switch valType[:2] {
+	case arrayType:
+		return generatorType(vType, generator.NewArrayResult)
+	case setType:
+		return generatorType(vType, generator.NewSetResult)
+	case mapType:
+		vTypeSplited := strings.Split(vType, ",")
+		if len(vTypeSplited) != 2 {
+			return nil, ErrUnknownType
+		}
+		keyType, ok := generatorTypes[strings.TrimSpace(vTypeSplited[0])]
+		if !ok {
+			return nil, ErrUnknownType
+		}
+		valueType, ok := generatorTypes[strings.TrimSpace(vTypeSplited[1])]
+		if !ok {
+			return nil, ErrUnknownType
+		}
+		return generator.NewMapResult(keyType, valueType), nil
+	}
+	return generatorType(valType, generator.NewSimpleResult)
+}
+
+func generatorType(valType string, newResult func(string) *generator.Result) (*generator.Result, error) {
+	genType, ok := generatorTypes[valType]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	return newResult(genType), nil
+}
+
 func parse(val string, valType string) (generator.Argument, error) {
+	if len(valType) < 2 {
+		return nil, ErrUnknownType
+	}
 	starting := valType[:2]
 	splited := []string{valType}
 	var argObj generator.Argument
@@ -60,6 +121,9 @@ func parse(val string, valType string) (generator.Argument, error) {
 		splited = strings.Split(valType, mapType)
 		vType := splited[1]
 		vTypeSplited := strings.Split(vType, ",")
+		if len(vTypeSplited) != 2 {
+			return nil, ErrUnknownType
+		}
 		keyType := vTypeSplited[0]
 		valueType := vTypeSplited[1]
 		argObj, err = parseMap(val, keyType, valueType)
@@ -110,7 +174,7 @@ func parseSimple(val string, baseType string) (*generator.Simple, error) {
 		}
 		res = generator.NewSimple(result)
 	default:
-		return nil, errors.New("unknown type")
+		return nil, ErrUnknownType
 	}
 	return res, nil
 }
@@ -119,7 +183,10 @@ func parseMapIntInt(val string) (map[int]int, error) {
 	splited := toStringArray(val)
 	result := make(map[int]int)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseInt(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -137,7 +204,10 @@ func parseMapIntFloat(val string) (map[int]float64, error) {
 	splited := toStringArray(val)
 	result := make(map[int]float64)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseInt(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -155,7 +225,10 @@ func parseMapIntBool(val string) (map[int]bool, error) {
 	splited := toStringArray(val)
 	result := make(map[int]bool)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseInt(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -173,7 +246,10 @@ func parseMapIntString(val string) (map[int]string, error) {
 	splited := toStringArray(val)
 	result := make(map[int]string)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseInt(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -191,7 +267,10 @@ func parseMapFloatInt(val string) (map[float64]int, error) {
 	splited := toStringArray(val)
 	result := make(map[float64]int)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseFloat(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -209,7 +288,10 @@ func parseMapFloatFloat(val string) (map[float64]float64, error) {
 	splited := toStringArray(val)
 	result := make(map[float64]float64)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseFloat(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -227,7 +309,10 @@ func parseMapFloatBool(val string) (map[float64]bool, error) {
 	splited := toStringArray(val)
 	result := make(map[float64]bool)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseFloat(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -245,7 +330,10 @@ func parseMapFloatString(val string) (map[float64]string, error) {
 	splited := toStringArray(val)
 	result := make(map[float64]string)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseFloat(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -263,7 +351,10 @@ func parseMapBoolInt(val string) (map[bool]int, error) {
 	splited := toStringArray(val)
 	result := make(map[bool]int)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseBool(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -281,7 +372,10 @@ func parseMapBoolFloat(val string) (map[bool]float64, error) {
 	splited := toStringArray(val)
 	result := make(map[bool]float64)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseBool(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -299,7 +393,10 @@ func parseMapBoolBool(val string) (map[bool]bool, error) {
 	splited := toStringArray(val)
 	result := make(map[bool]bool)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseBool(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -317,7 +414,10 @@ func parseMapBoolString(val string) (map[bool]string, error) {
 	splited := toStringArray(val)
 	result := make(map[bool]string)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseBool(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -335,7 +435,10 @@ func parseMapStringInt(val string) (map[string]int, error) {
 	splited := toStringArray(val)
 	result := make(map[string]int)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseString(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -353,7 +456,10 @@ func parseMapStringFloat(val string) (map[string]float64, error) {
 	splited := toStringArray(val)
 	result := make(map[string]float64)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseString(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -371,7 +477,10 @@ func parseMapStringBool(val string) (map[string]bool, error) {
 	splited := toStringArray(val)
 	result := make(map[string]bool)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseString(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -389,7 +498,10 @@ func parseMapStringString(val string) (map[string]string, error) {
 	splited := toStringArray(val)
 	result := make(map[string]string)
 	for _, value := range splited {
-		keyValueList := strings.Split(value, ":")
+		keyValueList, err := splitEntry(value)
+		if err != nil {
+			return nil, err
+		}
 		parsedKey, err := parseString(keyValueList[0])
 		if err != nil {
 			return nil, err
@@ -433,7 +545,7 @@ func parseMap(val, baseKeyType, baseValueType string) (*generator.InputMap, erro
 			}
 			res = generator.NewMap(result)
 		default:
-			return nil, errors.New("unknown type")
+			return nil, ErrUnknownType
 		}
 	case floatType:
 		switch baseValueType {
@@ -462,7 +574,7 @@ func parseMap(val, baseKeyType, baseValueType string) (*generator.InputMap, erro
 			}
 			res = generator.NewMap(result)
 		default:
-			return nil, errors.New("unknown type")
+			return nil, ErrUnknownType
 		}
 	case boolType:
 		switch baseValueType {
@@ -491,7 +603,7 @@ func parseMap(val, baseKeyType, baseValueType string) (*generator.InputMap, erro
 			}
 			res = generator.NewMap(result)
 		default:
-			return nil, errors.New("unknown type")
+			return nil, ErrUnknownType
 		}
 	case stringType:
 		switch baseValueType {
@@ -520,10 +632,10 @@ func parseMap(val, baseKeyType, baseValueType string) (*generator.InputMap, erro
 			}
 			res = generator.NewMap(result)
 		default:
-			return nil, errors.New("unknown type")
+			return nil, ErrUnknownType
 		}
 	default:
-		return nil, errors.New("unknown type")
+		return nil, ErrUnknownType
 	}
 	return res, nil
 }
@@ -551,8 +663,24 @@ func parseString(value string) (string, error) {
 	return value, nil
 }
 
+// splitEntry splits map entry to key and value, value can contain colons
+func splitEntry(entry string) ([]string, error) {
+	keyValue := strings.SplitN(entry, ":", 2)
+	if len(keyValue) != 2 {
+		return nil, ErrMalformedEntry
+	}
+	return keyValue, nil
+}
+
 func toStringArray(val string) []string {
-	val = val[1 : len(val)-2]
+	val = strings.TrimSpace(val)
+	if len(val) < 2 {
+		return nil
+	}
+	val = val[1 : len(val)-1]
+	if strings.TrimSpace(val) == "" {
+		return nil
+	}
 	return strings.Split(val, ",")
 }
 
@@ -597,7 +725,15 @@ func parseBoolArray(val string) ([]bool, error) {
 
 func parseStringArray(val string) ([]string, error) {
 	splited := toStringArray(val)
-	return splited, nil
+	var result []string
+	for _, value := range splited {
+		parsedValue, err := parseString(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, parsedValue)
+	}
+	return result, nil
 }
 
 func parseArray(val, baseType string) (*generator.Array, error) {
@@ -628,7 +764,7 @@ func parseArray(val, baseType string) (*generator.Array, error) {
 		}
 		res = generator.NewArray(result)
 	default:
-		return nil, errors.New("unknown type")
+		return nil, ErrUnknownType
 	}
 	return res, nil
 }
@@ -661,7 +797,7 @@ func parseSet(val, baseType string) (*generator.Set, error) {
 		}
 		res = generator.NewSet(result)
 	default:
-		return nil, errors.New("unknown type")
+		return nil, ErrUnknownType
 	}
 	return res, nil
 }
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Strovala/crackview/generator"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     string
+		valType string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "int", val: " 42 ", valType: "int", want: 42},
+		{name: "negative int", val: "-7", valType: "int", want: -7},
+		{name: "float", val: "2.5", valType: "float", want: 2.5},
+		{name: "bool", val: "true", valType: "bool", want: true},
+		{name: "string keeps quotes", val: `"abc"`, valType: "string", want: `"abc"`},
+		{name: "int array", val: "[1, 3, 5]", valType: "[]int", want: []int{1, 3, 5}},
+		{name: "empty array", val: "[]", valType: "[]int", want: []int(nil)},
+		{name: "string array", val: `["a", "b"]`, valType: "[]string", want: []string{`"a"`, `"b"`}},
+		{name: "float set", val: "(3.4, 5.6)", valType: "()float", want: []float64{3.4, 5.6}},
+		{name: "int map", val: "{1: 2, 3: 4}", valType: "{}int,int", want: map[int]int{1: 2, 3: 4}},
+		{name: "map value with colon", val: `{1: "a:b"}`, valType: "{}int,string", want: map[int]string{1: `"a:b"`}},
+		{name: "string bool map", val: `{"foo": false}`, valType: "{}string,bool", want: map[string]bool{`"foo"`: false}},
+		{name: "malformed int", val: "abc", valType: "int", wantErr: true},
+		{name: "empty int", val: "", valType: "int", wantErr: true},
+		{name: "malformed bool", val: "yes", valType: "bool", wantErr: true},
+		{name: "malformed array element", val: "[1, x]", valType: "[]int", wantErr: true},
+		{name: "unknown type", val: "1", valType: "long", wantErr: true},
+		{name: "unknown element type", val: "[1]", valType: "[]long", wantErr: true},
+		{name: "array without element type", val: "[1]", valType: "[]", wantErr: true},
+		{name: "map without value type", val: "{1: 2}", valType: "{}int", wantErr: true},
+		{name: "map entry without value", val: "{1}", valType: "{}int,int", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseValue(tt.val, tt.valType)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseValue(%q, %q) = %#v, want error", tt.val, tt.valType, got.Value())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseValue(%q, %q) error: %v", tt.val, tt.valType, err)
+			}
+			if !reflect.DeepEqual(got.Value(), tt.want) {
+				t.Errorf("ParseValue(%q, %q) = %#v, want %#v", tt.val, tt.valType, got.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		valType string
+		want    *generator.Result
+		wantErr bool
+	}{
+		{valType: "int", want: generator.NewSimpleResult("int")},
+		{valType: " float ", want: generator.NewSimpleResult("float64")},
+		{valType: "[]string", want: generator.NewArrayResult("string")},
+		{valType: "()bool", want: generator.NewSetResult("bool")},
+		{valType: "{}int,string", want: generator.NewMapResult("int", "string")},
+		{valType: "{}int, float", want: generator.NewMapResult("int", "float64")},
+		{valType: "", wantErr: true},
+		{valType: "long", wantErr: true},
+		{valType: "[]long", wantErr: true},
+		{valType: "{}int", wantErr: true},
+		{valType: "{}int,long", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.valType, func(t *testing.T) {
+			got, err := ParseType(tt.valType)
+			if tt.wantErr {
+				if err != ErrUnknownType {
+					t.Fatalf("ParseType(%q) error = %v, want %v", tt.valType, err, ErrUnknownType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseType(%q) error: %v", tt.valType, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseType(%q) = %+v, want %+v", tt.valType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	args, err := Parse("[1, 3, 5] => []int\n\n9 => int\n")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	var got []interface{}
+	for _, arg := range args {
+		got = append(got, arg.Value())
+	}
+	want := []interface{}{[]int{1, 3, 5}, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %#v, want %#v", got, want)
+	}
+	if _, err := Parse("[1, x] => []int"); err == nil {
+		t.Error("Parse of malformed value succeeded")
+	}
+}
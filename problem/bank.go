@@ -0,0 +1,72 @@
+package problem
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	pkgErrors "github.com/pkg/errors"
+)
+
+// Errors of problem bank
+var (
+	ErrProblemNotFound = errors.New("Problem not found")
+	ErrDuplicateID     = errors.New("Problem id is used by more than one problem")
+)
+
+// Bank holds all loaded problems
+type Bank struct {
+	problems map[string]*Problem
+}
+
+// LoadBank loads every problem from subdirectories of dir, ids of problems
+// have to be unique
+func LoadBank(dir string) (*Bank, error) {
+	bank := &Bank{problems: make(map[string]*Problem)}
+	if dir == "" {
+		return bank, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	dirs := make(map[string]string)
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		problemDir := filepath.Join(dir, file.Name())
+		p, err := Load(problemDir)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := dirs[p.ID]; ok {
+			return nil, pkgErrors.Wrapf(ErrDuplicateID, "problem %v in %v and %v", p.ID, other, problemDir)
+		}
+		dirs[p.ID] = problemDir
+		bank.problems[p.ID] = p
+	}
+	return bank, nil
+}
+
+// List returns all problems sorted by id
+func (b *Bank) List() []*Problem {
+	var result []*Problem
+	for _, p := range b.problems {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Get returns problem with given id
+func (b *Bank) Get(id string) (*Problem, error) {
+	p, ok := b.problems[id]
+	if !ok {
+		return nil, ErrProblemNotFound
+	}
+	return p, nil
+}
@@ -0,0 +1,85 @@
+package problem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// writeBank writes problems with given definitions into directories of new
+// bank directory, directories are named by keys of problems
+func writeBank(t *testing.T, problems map[string]string) string {
+	root := t.TempDir()
+	for name, definition := range problems {
+		if err := os.MkdirAll(filepath.Join(root, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, name, definitionFile), []byte(definition), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLoadBank(t *testing.T) {
+	const signature = "signature:\n  returns: int\n"
+	tests := []struct {
+		name     string
+		problems map[string]string
+		want     []string
+		wantErr  error
+	}{
+		{name: "ids from directories", problems: map[string]string{"b-sum": signature, "a_sum2": signature}, want: []string{"a_sum2", "b-sum"}},
+		{name: "id from definition", problems: map[string]string{"Sum Problem": "id: sum\n" + signature}, want: []string{"sum"}},
+		{name: "invalid directory name", problems: map[string]string{"Sum Problem": signature}, wantErr: ErrInvalidID},
+		{name: "invalid id", problems: map[string]string{"sum": "id: ../sum\n" + signature}, wantErr: ErrInvalidID},
+		{name: "duplicate id", problems: map[string]string{"sum": signature, "sum-copy": "id: sum\n" + signature}, wantErr: ErrDuplicateID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank, err := LoadBank(writeBank(t, tt.problems))
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("LoadBank error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var ids []string
+			for _, p := range bank.List() {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBankSkipsFiles(t *testing.T) {
+	root := writeBank(t, map[string]string{"sum": "signature:\n  returns: int\n"})
+	if err := ioutil.WriteFile(filepath.Join(root, "README.md"), []byte("problems"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bank, err := LoadBank(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bank.Get("sum"); err != nil {
+		t.Errorf("Get(sum) error = %v", err)
+	}
+	if _, err := bank.Get("README.md"); err != ErrProblemNotFound {
+		t.Errorf("Get(README.md) error = %v, want %v", err, ErrProblemNotFound)
+	}
+}
+
+func TestNaturalSort(t *testing.T) {
+	names := []string{"10", "2", "1", "11", "03"}
+	NaturalSort(names)
+	if want := []string{"1", "2", "03", "10", "11"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("NaturalSort() = %v, want %v", names, want)
+	}
+}
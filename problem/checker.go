@@ -0,0 +1,281 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/generator"
+	"github.com/Strovala/crackview/metrics"
+	"github.com/Strovala/crackview/parser"
+	"github.com/Strovala/crackview/runner"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Types of checkers comparing expected and actual results
+const (
+	// CheckerExact compares values exactly, elements of sets in any order
+	CheckerExact = "exact"
+	// CheckerTokens compares strings by their whitespace separated tokens
+	CheckerTokens = "tokens"
+	// CheckerFloat compares floats with absolute or relative epsilon
+	CheckerFloat = "float"
+	// CheckerUnordered compares elements of list in any order
+	CheckerUnordered = "unordered"
+	// CheckerUnorderedNested compares elements of list and of lists nested in it in any order
+	CheckerUnorderedNested = "unordered-nested"
+	// CheckerSet compares distinct elements of list in any order
+	CheckerSet = "set"
+	// CheckerProgram runs checker program of problem
+	CheckerProgram = "program"
+)
+
+// Files checker program reads, each holds value in parser syntax, verdict is
+// written to output file, first line is Accepted or WrongAnswer and rest is message
+const (
+	CheckerInputFile    = "input.txt"
+	CheckerExpectedFile = "expected.txt"
+	CheckerActualFile   = "actual.txt"
+)
+
+// DefaultEpsilon is absolute epsilon of float checker which declares neither epsilon
+const DefaultEpsilon = 1e-6
+
+// Errors of checkers
+var (
+	ErrUnknownChecker = errors.New("Unknown checker type")
+	ErrCheckerFailed  = errors.New("Checker program failed")
+)
+
+// Checker decides whether actual result of solution matches expected one,
+// results are compared as values parsed from parser syntax. Empty type is exact
+type Checker struct {
+	Type       string  `json:"type,omitempty" yaml:"type,omitempty"`
+	AbsEpsilon float64 `json:"absEpsilon,omitempty" yaml:"absEpsilon,omitempty"`
+	RelEpsilon float64 `json:"relEpsilon,omitempty" yaml:"relEpsilon,omitempty"`
+	// TimeLimit of checker program is in milliseconds, zero means default limit from config
+	TimeLimit int `json:"-" yaml:"timeLimit,omitempty"`
+	// Lang and Source are checker program loaded from checker directory
+	Lang   string `json:"-" yaml:"-"`
+	Source string `json:"-" yaml:"-"`
+}
+
+func (c *Checker) validate() error {
+	switch c.Type {
+	case "", CheckerExact, CheckerTokens, CheckerFloat, CheckerUnordered, CheckerUnorderedNested, CheckerSet:
+	case CheckerProgram:
+		if c.Source == "" {
+			return fmt.Errorf("checker program %v.* is missing", filepath.Join(checkerDir, checkerName))
+		}
+	default:
+		return fmt.Errorf("%v %v", ErrUnknownChecker, c.Type)
+	}
+	if c.AbsEpsilon < 0 || c.RelEpsilon < 0 {
+		return errors.New("checker epsilon can not be negative")
+	}
+	return nil
+}
+
+// Check compares expected and actual values of given type, actual value which
+// can not be parsed is treated as wrong answer. Message explains verdict of
+// checker program, other checkers leave it empty
+func (c *Checker) Check(input, expected, actual, valType string, logger *zap.Logger) (bool, string, error) {
+	expectedArg, err := parser.ParseValue(expected, valType)
+	if err != nil {
+		return false, "", err
+	}
+	actualArg, err := parser.ParseValue(actual, valType)
+	if err != nil {
+		metrics.ParseErrors.WithLabelValues(metrics.SourceResult).Inc()
+		return false, "", nil
+	}
+	if c.Type == CheckerProgram {
+		return c.runProgram(input, expected, actual, logger)
+	}
+	checkerType := c.Type
+	if _, ok := expectedArg.(*generator.Set); ok && (checkerType == "" || checkerType == CheckerExact) {
+		checkerType = CheckerUnordered
+	}
+	expectedValue := normalize(reflect.ValueOf(expectedArg.Value()), checkerType, true)
+	actualValue := normalize(reflect.ValueOf(actualArg.Value()), checkerType, true)
+	if checkerType == CheckerFloat {
+		abs, rel := c.AbsEpsilon, c.RelEpsilon
+		if abs == 0 && rel == 0 {
+			abs = DefaultEpsilon
+		}
+		return closeValues(reflect.ValueOf(expectedValue), reflect.ValueOf(actualValue), abs, rel), "", nil
+	}
+	return reflect.DeepEqual(expectedValue, actualValue), "", nil
+}
+
+// runProgram runs checker program in temporary directory with input, expected
+// and actual values written to files it reads
+func (c *Checker) runProgram(input, expected, actual string, logger *zap.Logger) (bool, string, error) {
+	if logger == nil {
+		logger = zap.L()
+	}
+	dir, err := ioutil.TempDir(viper.GetString("workdir"), "crackview-checker")
+	if err != nil {
+		return false, "", err
+	}
+	defer os.RemoveAll(dir)
+	mainName, ok := execution.MainNames[c.Lang]
+	if !ok {
+		return false, "", execution.ErrUnknownLanguage
+	}
+	files := map[string]string{
+		mainName:            c.Source,
+		CheckerInputFile:    input,
+		CheckerExpectedFile: expected,
+		CheckerActualFile:   actual,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return false, "", err
+		}
+	}
+	executor, err := execution.NewExecutor(c.Lang, dir)
+	if err != nil {
+		return false, "", err
+	}
+	timeLimit := time.Duration(c.TimeLimit) * time.Millisecond
+	if timeLimit == 0 {
+		timeLimit = time.Duration(viper.GetInt("timeLimit")) * time.Millisecond
+	}
+	executor.SetTimeLimit(timeLimit)
+	executor.SetLogger(logger.With(zap.String("checker", c.Lang)))
+	executor.SetCache(runner.Cache())
+	flags, err := runner.SelectFlags(c.Lang, nil)
+	if err != nil {
+		return false, "", err
+	}
+	executor.SetFlags(flags)
+	result, err := executor.Execute()
+	if err != nil {
+		return false, "", err
+	}
+	if result.Verdict != execution.VerdictOK {
+		return false, "", fmt.Errorf("%v with %v:\n%v", ErrCheckerFailed, result.Verdict, result.Error)
+	}
+	lines := strings.SplitN(strings.TrimSpace(result.Result), "\n", 2)
+	message := ""
+	if len(lines) == 2 {
+		message = strings.TrimSpace(lines[1])
+	}
+	switch strings.TrimSpace(lines[0]) {
+	case VerdictAccepted:
+		return true, message, nil
+	case VerdictWrongAnswer:
+		return false, message, nil
+	}
+	return false, "", fmt.Errorf("%v: unknown verdict %q", ErrCheckerFailed, lines[0])
+}
+
+// normalize converts value to form in which values matching by checker type
+// are deeply equal, lists are converted to []interface{}
+func normalize(val reflect.Value, checkerType string, top bool) interface{} {
+	switch val.Kind() {
+	case reflect.String:
+		if checkerType == CheckerTokens {
+			// parsed strings keep their quotes
+			text := val.String()
+			if unquoted, err := strconv.Unquote(text); err == nil {
+				text = unquoted
+			}
+			return strings.Join(strings.Fields(text), " ")
+		}
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, val.Len())
+		for i := range items {
+			items[i] = normalize(val.Index(i), checkerType, false)
+		}
+		if checkerType == CheckerUnorderedNested || (top && (checkerType == CheckerUnordered || checkerType == CheckerSet)) {
+			sortItems(items)
+		}
+		if top && checkerType == CheckerSet {
+			items = distinct(items)
+		}
+		return items
+	case reflect.Map:
+		if checkerType == CheckerTokens {
+			items := make(map[interface{}]interface{}, val.Len())
+			for _, key := range val.MapKeys() {
+				items[normalize(key, checkerType, false)] = normalize(val.MapIndex(key), checkerType, false)
+			}
+			return items
+		}
+	}
+	return val.Interface()
+}
+
+// sortItems sorts items by their text representation
+func sortItems(items []interface{}) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return fmt.Sprintf("%v", items[i]) < fmt.Sprintf("%v", items[j])
+	})
+}
+
+// distinct removes repeated items from sorted items
+func distinct(items []interface{}) []interface{} {
+	var result []interface{}
+	for _, item := range items {
+		if len(result) == 0 || !reflect.DeepEqual(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// closeValues compares values deeply treating floats within epsilon as equal
+func closeValues(expected, actual reflect.Value, abs, rel float64) bool {
+	if expected.Kind() == reflect.Interface {
+		expected = expected.Elem()
+	}
+	if actual.Kind() == reflect.Interface {
+		actual = actual.Elem()
+	}
+	if expected.Kind() != actual.Kind() {
+		return false
+	}
+	switch expected.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return closeFloats(expected.Float(), actual.Float(), abs, rel)
+	case reflect.Slice, reflect.Array:
+		if expected.Len() != actual.Len() {
+			return false
+		}
+		for i := 0; i < expected.Len(); i++ {
+			if !closeValues(expected.Index(i), actual.Index(i), abs, rel) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if expected.Len() != actual.Len() {
+			return false
+		}
+		for _, key := range expected.MapKeys() {
+			value := actual.MapIndex(key)
+			if !value.IsValid() || !closeValues(expected.MapIndex(key), value, abs, rel) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(expected.Interface(), actual.Interface())
+}
+
+func closeFloats(expected, actual, abs, rel float64) bool {
+	diff := math.Abs(expected - actual)
+	return diff <= abs || diff <= rel*math.Max(math.Abs(expected), math.Abs(actual))
+}
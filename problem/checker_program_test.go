@@ -0,0 +1,149 @@
+package problem
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"go.uber.org/zap"
+)
+
+// pairChecker accepts any pair of indices of numbers which sum to target
+const pairChecker = `import ast
+
+def value(name):
+    with open(name) as f:
+        return ast.literal_eval(f.read().split("=>")[0].strip())
+
+lines = open("input.txt").read().split("\n")
+nums = ast.literal_eval(lines[0].split("=>")[0].strip())
+target = int(lines[1].split("=>")[0].strip())
+i, j = value("actual.txt")
+with open("output.txt", "w") as out:
+    if i != j and nums[i] + nums[j] == target:
+        out.write("Accepted\npair sums to %d" % target)
+    else:
+        out.write("WrongAnswer\n%d + %d is not %d" % (nums[i], nums[j], target))
+`
+
+// pairProblem returns problem whose tests have more than one right answer
+func pairProblem() *Problem {
+	return &Problem{
+		ID:        "pair",
+		Signature: Signature{Args: []string{"[]int", "int"}, Returns: "[]int"},
+		Checker:   Checker{Type: CheckerProgram, Lang: execution.Python, Source: pairChecker},
+		Tests: []*Test{
+			{Name: "1", Input: "[1, 2, 3, 4] => []int\n5 => int", Expected: "[0, 3]"},
+		},
+	}
+}
+
+func TestCheckProgram(t *testing.T) {
+	requirePython(t)
+	const input = "[1, 2, 3, 4] => []int\n5 => int"
+	tests := []struct {
+		name        string
+		source      string
+		timeLimit   int
+		actual      string
+		want        bool
+		wantMessage string
+		wantErr     error
+	}{
+		{name: "expected answer", source: pairChecker, actual: "[0, 3]", want: true, wantMessage: "pair sums to 5"},
+		{name: "other answer", source: pairChecker, actual: "[1, 2]", want: true, wantMessage: "pair sums to 5"},
+		{name: "wrong answer", source: pairChecker, actual: "[0, 1]", wantMessage: "1 + 2 is not 5"},
+		{name: "unparsable actual is not checked", source: "raise Exception()", actual: "nope", want: false},
+		{name: "verdict without message", source: "open('output.txt', 'w').write('Accepted')", actual: "[0, 3]", want: true},
+		{name: "crash", source: "raise Exception('broken checker')", actual: "[0, 3]", wantErr: ErrCheckerFailed},
+		{name: "unknown verdict", source: "open('output.txt', 'w').write('Maybe')", actual: "[0, 3]", wantErr: ErrCheckerFailed},
+		{name: "time limit", source: "while True:\n    pass\n", timeLimit: 200, actual: "[0, 3]", wantErr: ErrCheckerFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{Type: CheckerProgram, Lang: execution.Python, Source: tt.source, TimeLimit: tt.timeLimit}
+			got, message, err := c.Check(input, "[0, 3]", tt.actual, "[]int", zap.NewNop())
+			if tt.wantErr != nil {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr.Error()) {
+					t.Fatalf("Check error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || message != tt.wantMessage {
+				t.Errorf("Check(%v) = %v %q, want %v %q", tt.actual, got, message, tt.want, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestSubmitWithCheckerProgram(t *testing.T) {
+	requirePython(t)
+	tests := []struct {
+		name        string
+		solution    string
+		verdict     string
+		wantMessage string
+	}{
+		{name: "other answer", solution: "class Solution:\n    def code(nums, target):\n        return [1, 2]\n", verdict: VerdictAccepted, wantMessage: "pair sums to 5"},
+		{name: "wrong answer", solution: "class Solution:\n    def code(nums, target):\n        return [0, 0]\n", verdict: VerdictWrongAnswer, wantMessage: "1 + 1 is not 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pairProblem().Submit(execution.Python, tt.solution, nil, zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Verdict != tt.verdict || result.Tests[0].Message != tt.wantMessage {
+				t.Errorf("submission = %v %q, want %v %q", result.Verdict, result.Tests[0].Message, tt.verdict, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestLoadCheckerProgram(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pair")
+	if err := pairProblem().Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Checker.Type != CheckerProgram || p.Checker.Lang != execution.Python || p.Checker.Source != pairChecker {
+		t.Errorf("checker = %+v, want python checker program", p.Checker)
+	}
+
+	// checker written in two languages is ambiguous
+	if err := writeFile(filepath.Join(dir, checkerDir, checkerName+".cpp"), "int main() {}\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("Load of problem with two checker programs did not fail")
+	}
+}
+
+func TestCheckerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker Checker
+		wantErr bool
+	}{
+		{name: "default", checker: Checker{}},
+		{name: "program", checker: Checker{Type: CheckerProgram, Lang: execution.Python, Source: pairChecker}},
+		{name: "program without source", checker: Checker{Type: CheckerProgram}, wantErr: true},
+		{name: "unknown type", checker: Checker{Type: "fuzzy"}, wantErr: true},
+		{name: "negative epsilon", checker: Checker{Type: CheckerFloat, AbsEpsilon: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.checker.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package problem
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		checker  Checker
+		expected string
+		actual   string
+		valType  string
+		want     bool
+	}{
+		{name: "exact", expected: "[1, 2]", actual: "[1, 2]", valType: "[]int", want: true},
+		{name: "exact order matters", expected: "[1, 2]", actual: "[2, 1]", valType: "[]int"},
+		{name: "exact set in any order", expected: "(1, 2)", actual: "(2, 1)", valType: "()int", want: true},
+		{name: "unparsable actual", expected: "1", actual: "x", valType: "int"},
+		{name: "tokens", checker: Checker{Type: CheckerTokens}, expected: `"a  b\tc"`, actual: `" a b c "`, valType: "string", want: true},
+		{name: "tokens differ", checker: Checker{Type: CheckerTokens}, expected: `"a b"`, actual: `"ab"`, valType: "string"},
+		{name: "tokens of list", checker: Checker{Type: CheckerTokens}, expected: `["a b"]`, actual: `["a  b"]`, valType: "[]string", want: true},
+		{name: "tokens of map", checker: Checker{Type: CheckerTokens}, expected: `{"k ": " v"}`, actual: `{"k": "v"}`, valType: "{}string,string", want: true},
+		{name: "unordered", checker: Checker{Type: CheckerUnordered}, expected: "[3, 1, 2]", actual: "[1, 2, 3]", valType: "[]int", want: true},
+		{name: "unordered counts repeats", checker: Checker{Type: CheckerUnordered}, expected: "[1, 1, 2]", actual: "[1, 2, 2]", valType: "[]int"},
+		{name: "set", checker: Checker{Type: CheckerSet}, expected: "[1, 1, 2]", actual: "[2, 1]", valType: "[]int", want: true},
+		{name: "set differs", checker: Checker{Type: CheckerSet}, expected: "[1, 2]", actual: "[1, 3]", valType: "[]int"},
+		{name: "float default epsilon", checker: Checker{Type: CheckerFloat}, expected: "0.1", actual: "0.1000001", valType: "float", want: true},
+		{name: "float outside default epsilon", checker: Checker{Type: CheckerFloat}, expected: "0.1", actual: "0.1001", valType: "float"},
+		{name: "float absolute epsilon", checker: Checker{Type: CheckerFloat, AbsEpsilon: 0.01}, expected: "[1.0, 2.0]", actual: "[1.005, 1.995]", valType: "[]float", want: true},
+		{name: "float relative epsilon", checker: Checker{Type: CheckerFloat, RelEpsilon: 0.01}, expected: "1000.0", actual: "1009.0", valType: "float", want: true},
+		{name: "float relative epsilon exceeded", checker: Checker{Type: CheckerFloat, RelEpsilon: 0.01}, expected: "1000.0", actual: "1011.0", valType: "float"},
+		{name: "float map", checker: Checker{Type: CheckerFloat, AbsEpsilon: 0.1}, expected: "{1: 2.0}", actual: "{1: 2.05}", valType: "{}int,float", want: true},
+		{name: "float map missing key", checker: Checker{Type: CheckerFloat, AbsEpsilon: 0.1}, expected: "{1: 2.0}", actual: "{2: 2.0}", valType: "{}int,float"},
+		{name: "float list length", checker: Checker{Type: CheckerFloat}, expected: "[1.0]", actual: "[1.0, 2.0]", valType: "[]float"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.checker.Check("", tt.expected, tt.actual, tt.valType, nil)
+			if err != nil {
+				t.Fatalf("Check error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Check(%v, %v) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnparsableExpected(t *testing.T) {
+	if _, _, err := (&Checker{}).Check("", "x", "1", "int", nil); err == nil {
+		t.Error("Check of unparsable expected value succeeded")
+	}
+}
+
+func TestNormalizeUnorderedNested(t *testing.T) {
+	expected := normalize(reflect.ValueOf([][]int{{2, 1}, {4, 3}}), CheckerUnorderedNested, true)
+	actual := normalize(reflect.ValueOf([][]int{{3, 4}, {1, 2}}), CheckerUnorderedNested, true)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("normalize = %v and %v, want equal", expected, actual)
+	}
+	// only top level list is unordered
+	expected = normalize(reflect.ValueOf([][]int{{2, 1}}), CheckerUnordered, true)
+	actual = normalize(reflect.ValueOf([][]int{{1, 2}}), CheckerUnordered, true)
+	if reflect.DeepEqual(expected, actual) {
+		t.Errorf("normalize = %v and %v, want different", expected, actual)
+	}
+}
+
+func TestCloseValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		want     bool
+	}{
+		{name: "floats", expected: 1.0, actual: 1.05, want: true},
+		{name: "nested floats", expected: []interface{}{[]interface{}{1.0}}, actual: []interface{}{[]interface{}{1.05}}, want: true},
+		{name: "far floats", expected: 1.0, actual: 1.2},
+		{name: "different kinds", expected: 1.0, actual: 1},
+		{name: "other values", expected: "a", actual: "a", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closeValues(reflect.ValueOf(tt.expected), reflect.ValueOf(tt.actual), 0.1, 0); got != tt.want {
+				t.Errorf("closeValues(%v, %v) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
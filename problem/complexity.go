@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Strovala/crackview/complexity"
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/stress"
+	"go.uber.org/zap"
+)
+
+// Defaults of complexity estimation
+const (
+	DefaultComplexityMinSize = 8
+	DefaultComplexityMaxSize = 16384
+	DefaultComplexityRepeats = 3
+)
+
+// maxComplexitySizes caps input size per language. Java inputs are literals in
+// main method whose bytecode can not exceed 64KB, inputs of 2048 elements of
+// few arguments stay below it and larger ones would fail to compile
+var maxComplexitySizes = map[string]int{
+	execution.Java: 2048,
+}
+
+// ErrNotScalable is returned when no argument of problem grows with input size
+var ErrNotScalable = errors.New("Problem has no argument which grows with input size, mark size argument as scaled in generator")
+
+// ComplexityOptions configure complexity estimation of solution, inputs have
+// sizes doubling from MinSize to MaxSize
+type ComplexityOptions struct {
+	Lang     string
+	Solution string
+	// Flags are requested flags of language, they are optional
+	Flags   *execution.Flags
+	MinSize int
+	// MaxSize is capped for languages which can not compile larger inputs, java
+	// inputs are at most 2048 elements
+	MaxSize int
+	// Repeats is number of runs per size, fastest one is measured to reduce noise
+	Repeats int
+	// Duration stops measuring early, zero means no limit
+	Duration time.Duration
+	// Seed makes inputs reproducible, zero means seed from current time
+	Seed   int64
+	Logger *zap.Logger
+}
+
+// Complexity runs solution on generated inputs of growing size and estimates
+// its time complexity class. Measuring stops at first size solution does not
+// finish successfully on, that measurement is reported but not fitted
+func (p *Problem) Complexity(opts ComplexityOptions) (*complexity.Estimate, error) {
+	if len(p.Signature.Args) == 0 {
+		return nil, ErrNoSignatureArgs
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultComplexityMinSize
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultComplexityMaxSize
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.With(zap.String("problem", p.ID), zap.Int64("seed", opts.Seed))
+	if max, ok := maxComplexitySizes[opts.Lang]; ok && opts.MaxSize > max {
+		logger.Info("max size is capped for language", zap.String("lang", opts.Lang), zap.Int("maxSize", max))
+		opts.MaxSize = max
+	}
+	if opts.MinSize > opts.MaxSize {
+		opts.MinSize = opts.MaxSize
+	}
+	if opts.Repeats <= 0 {
+		opts.Repeats = DefaultComplexityRepeats
+	}
+	generator, err := stress.New(p.Signature.Args, p.Generator, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	if !generator.Scalable() {
+		return nil, ErrNotScalable
+	}
+
+	var measured, fitted []complexity.Measurement
+	start := time.Now()
+	for _, size := range complexity.Sizes(opts.MinSize, opts.MaxSize) {
+		if opts.Duration > 0 && time.Since(start) > opts.Duration {
+			break
+		}
+		measurement, err := p.measure(generator.InputOfSize(size), size, opts, logger)
+		if err != nil {
+			return nil, err
+		}
+		measured = append(measured, *measurement)
+		if measurement.Verdict != execution.VerdictOK {
+			break
+		}
+		fitted = append(fitted, *measurement)
+	}
+
+	estimate := complexity.Classify(fitted)
+	estimate.Measurements = measured
+	logger.Info("estimated complexity",
+		zap.String("lang", opts.Lang),
+		zap.String("class", estimate.Class),
+		zap.Int("sizes", len(measured)),
+	)
+	return estimate, nil
+}
+
+// measure runs solution on input repeatedly and returns fastest run, only
+// solution call is measured so startup and parsing of input which grows with
+// size do not make every solution look linear. Failed run is measured whole
+func (p *Problem) measure(input string, size int, opts ComplexityOptions, logger *zap.Logger) (*complexity.Measurement, error) {
+	var best *complexity.Measurement
+	for i := 0; i < opts.Repeats; i++ {
+		result, err := p.execute(input, opts.Lang, opts.Solution, opts.Flags, logger)
+		if err != nil {
+			return nil, err
+		}
+		if result.Verdict != execution.VerdictOK {
+			return &complexity.Measurement{Size: size, RunTime: result.RunTime, Verdict: result.Verdict}, nil
+		}
+		if best == nil || result.SolutionTime < best.RunTime {
+			best = &complexity.Measurement{Size: size, RunTime: result.SolutionTime, Verdict: result.Verdict}
+		}
+	}
+	return best, nil
+}
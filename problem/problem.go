@@ -0,0 +1,237 @@
+package problem
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/parser"
+	"github.com/Strovala/crackview/stress"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Files and directories problem is made of
+const (
+	definitionFile = "problem.yaml"
+	statementFile  = "statement.md"
+	starterDir     = "starter"
+	referenceDir   = "reference"
+	checkerDir     = "checker"
+	starterName    = "solution"
+	checkerName    = "checker"
+	testsDir       = "tests"
+	publicDir      = "public"
+	hiddenDir      = "hidden"
+	inputExt       = ".in"
+	outputExt      = ".out"
+)
+
+// Signature describes arguments and result of solution in parser syntax
+type Signature struct {
+	Args    []string `json:"args" yaml:"args"`
+	Returns string   `json:"returns" yaml:"returns"`
+}
+
+// Test is single test case of problem in parser syntax
+type Test struct {
+	Name     string `json:"name"`
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Hidden   bool   `json:"hidden"`
+}
+
+// Problem is interview problem defined on disk
+type Problem struct {
+	ID        string    `json:"id" yaml:"id"`
+	Title     string    `json:"title" yaml:"title"`
+	Signature Signature `json:"signature" yaml:"signature"`
+	// TimeLimit is in milliseconds, zero means default limit from config
+	TimeLimit int               `json:"timeLimit" yaml:"timeLimit"`
+	Statement string            `json:"statement" yaml:"-"`
+	Starter   map[string]string `json:"starter" yaml:"-"`
+	// References are reference solutions by language, they are never shown to candidates
+	References map[string]string `json:"-" yaml:"-"`
+	// Generator declares how random inputs for stress runs are generated, one spec per argument
+	Generator []stress.Arg `json:"-" yaml:"generator,omitempty"`
+	// Checker decides which results are accepted, default one compares them exactly
+	Checker Checker `json:"checker" yaml:"checker,omitempty"`
+	Tests   []*Test `json:"-" yaml:"-"`
+}
+
+// Examples returns tests which are visible to candidate
+func (p *Problem) Examples() []*Test {
+	var result []*Test
+	for _, test := range p.Tests {
+		if !test.Hidden {
+			result = append(result, test)
+		}
+	}
+	return result
+}
+
+// Load loads problem from directory, id defaults to directory name
+func Load(dir string) (*Problem, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, definitionFile))
+	if err != nil {
+		return nil, err
+	}
+	p := &Problem{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, errors.Wrapf(err, "problem %v", dir)
+	}
+	if p.ID == "" {
+		p.ID = filepath.Base(dir)
+	}
+	if !ValidID(p.ID) {
+		return nil, errors.Wrapf(ErrInvalidID, "problem %q in %v", p.ID, dir)
+	}
+	if p.Title == "" {
+		p.Title = p.ID
+	}
+	if p.Signature.Returns == "" {
+		return nil, fmt.Errorf("problem %v: signature has no return type", p.ID)
+	}
+	if _, err := parser.ParseType(p.Signature.Returns); err != nil {
+		return nil, errors.Wrapf(err, "problem %v: return type", p.ID)
+	}
+	if len(p.Generator) != 0 {
+		if len(p.Generator) > len(p.Signature.Args) {
+			return nil, fmt.Errorf("problem %v: generator declares more arguments than signature", p.ID)
+		}
+		if _, err := stress.New(p.Signature.Args, p.Generator, 0); err != nil {
+			return nil, errors.Wrapf(err, "problem %v: generator", p.ID)
+		}
+	}
+
+	statement, err := ioutil.ReadFile(filepath.Join(dir, statementFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	p.Statement = string(statement)
+
+	if p.Starter, err = loadSolutions(filepath.Join(dir, starterDir), starterName); err != nil {
+		return nil, err
+	}
+	if p.References, err = loadSolutions(filepath.Join(dir, referenceDir), starterName); err != nil {
+		return nil, err
+	}
+	checkers, err := loadSolutions(filepath.Join(dir, checkerDir), checkerName)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkers) > 1 {
+		return nil, fmt.Errorf("problem %v: checker program is written in more than one language", p.ID)
+	}
+	for lang, source := range checkers {
+		p.Checker.Lang, p.Checker.Source = lang, source
+	}
+	if err := p.Checker.validate(); err != nil {
+		return nil, errors.Wrapf(err, "problem %v", p.ID)
+	}
+
+	public, err := loadTests(filepath.Join(dir, testsDir, publicDir), false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem %v", p.ID)
+	}
+	hidden, err := loadTests(filepath.Join(dir, testsDir, hiddenDir), true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem %v", p.ID)
+	}
+	p.Tests = append(public, hidden...)
+	for _, test := range p.Tests {
+		if err := p.validate(test); err != nil {
+			return nil, errors.Wrapf(err, "problem %v: test %v", p.ID, test.Name)
+		}
+	}
+	return p, nil
+}
+
+// validate checks that test input matches signature and that expected output can be parsed
+func (p *Problem) validate(test *Test) error {
+	args, err := parser.Parse(test.Input)
+	if err != nil {
+		return err
+	}
+	if len(p.Signature.Args) != 0 && len(args) != len(p.Signature.Args) {
+		return fmt.Errorf("expected %v arguments, got %v", len(p.Signature.Args), len(args))
+	}
+	_, err = parser.ParseValue(test.Expected, p.Signature.Returns)
+	return err
+}
+
+// loadSolutions loads source file with name of every language which has one in dir
+func loadSolutions(dir, name string) (map[string]string, error) {
+	result := make(map[string]string)
+	for lang, mainName := range execution.MainNames {
+		solution, err := ioutil.ReadFile(filepath.Join(dir, name+filepath.Ext(mainName)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[lang] = string(solution)
+	}
+	return result, nil
+}
+
+// loadTests loads every input file in dir together with its output file
+func loadTests(dir string, hidden bool) ([]*Test, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, file := range files {
+		if filepath.Ext(file.Name()) == inputExt {
+			names = append(names, strings.TrimSuffix(file.Name(), inputExt))
+		}
+	}
+	NaturalSort(names)
+
+	var result []*Test
+	for _, name := range names {
+		input, err := ioutil.ReadFile(filepath.Join(dir, name+inputExt))
+		if err != nil {
+			return nil, err
+		}
+		expected, err := ioutil.ReadFile(filepath.Join(dir, name+outputExt))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &Test{
+			Name:     name,
+			Input:    string(input),
+			Expected: expectedValue(string(expected)),
+			Hidden:   hidden,
+		})
+	}
+	return result, nil
+}
+
+// NaturalSort sorts names of numbered tests so 2 comes before 10
+func NaturalSort(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+}
+
+// expectedValue strips optional type from expected output ex. [1, 2] => []int
+func expectedValue(expected string) string {
+	expected = strings.TrimSpace(expected)
+	if i := strings.Index(expected, "=>"); i >= 0 {
+		expected = expected[:i]
+	}
+	return strings.TrimSpace(expected)
+}
@@ -0,0 +1,101 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/Strovala/crackview/execution"
+	pkgErrors "github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// idPattern matches ids which are safe to use as directory names
+var idPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ErrInvalidID is returned when problem id is not made of lowercase letters, digits, dashes and underscores
+var ErrInvalidID = errors.New("Problem id can contain only lowercase letters, digits, dashes and underscores")
+
+// ValidID checks that id can be used as name of problem directory
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// Save writes problem to dir in layout Load reads, dir must not exist and it
+// is removed when problem can not be written completely
+func (p *Problem) Save(dir string) error {
+	if !ValidID(p.ID) {
+		return pkgErrors.Wrapf(ErrInvalidID, "problem %q", p.ID)
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("problem %v: %v already exists", p.ID, dir)
+	}
+	if err := p.save(dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+func (p *Problem) save(dir string) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, definitionFile), string(data)); err != nil {
+		return err
+	}
+	if p.Statement != "" {
+		if err := writeFile(filepath.Join(dir, statementFile), p.Statement); err != nil {
+			return err
+		}
+	}
+	if err := saveSolutions(filepath.Join(dir, starterDir), starterName, p.Starter); err != nil {
+		return err
+	}
+	if err := saveSolutions(filepath.Join(dir, referenceDir), starterName, p.References); err != nil {
+		return err
+	}
+	if p.Checker.Source != "" {
+		checker := map[string]string{p.Checker.Lang: p.Checker.Source}
+		if err := saveSolutions(filepath.Join(dir, checkerDir), checkerName, checker); err != nil {
+			return err
+		}
+	}
+	for _, test := range p.Tests {
+		testDir := filepath.Join(dir, testsDir, publicDir)
+		if test.Hidden {
+			testDir = filepath.Join(dir, testsDir, hiddenDir)
+		}
+		if err := writeFile(filepath.Join(testDir, test.Name+inputExt), test.Input+"\n"); err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(testDir, test.Name+outputExt), test.Expected+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveSolutions(dir, name string, solutions map[string]string) error {
+	for lang, solution := range solutions {
+		mainName, ok := execution.MainNames[lang]
+		if !ok {
+			return execution.ErrUnknownLanguage
+		}
+		if err := writeFile(filepath.Join(dir, name+filepath.Ext(mainName)), solution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,44 @@
+package problem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestValidID(t *testing.T) {
+	tests := map[string]bool{
+		"two-sum":    true,
+		"lru_cache2": true,
+		"":           false,
+		"Two-Sum":    false,
+		"../../x":    false,
+		"a/b":        false,
+		"a b":        false,
+		".":          false,
+	}
+	for id, want := range tests {
+		if got := ValidID(id); got != want {
+			t.Errorf("ValidID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestSaveInvalidID(t *testing.T) {
+	root, err := ioutil.TempDir("", "crackview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	p := &Problem{ID: "../escaped", Title: "Escaped"}
+	dir := filepath.Join(root, "problems", p.ID)
+	if err := p.Save(dir); errors.Cause(err) != ErrInvalidID {
+		t.Fatalf("Save error = %v, want %v", err, ErrInvalidID)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Save created %v", dir)
+	}
+}
@@ -0,0 +1,191 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/stress"
+	"go.uber.org/zap"
+)
+
+// DefaultStressIterations is number of random inputs stress run tries by default
+const DefaultStressIterations = 1000
+
+// Errors of stress runs
+var (
+	ErrNoSignatureArgs = errors.New("Problem signature does not declare argument types")
+	ErrReferenceFailed = errors.New("Reference solution failed")
+)
+
+// StressOptions configure stress run of solution against reference solution
+type StressOptions struct {
+	Lang     string
+	Solution string
+	// Flags are requested flags of language of solution, reference runs with default ones
+	Flags *execution.Flags
+	// ReferenceLang defaults to Lang when problem has reference in it, otherwise to any reference
+	ReferenceLang string
+	Iterations    int
+	// Duration stops run early, zero means no limit
+	Duration time.Duration
+	// Workers is number of inputs run at the same time, defaults to number of CPUs
+	Workers int
+	// Seed makes run reproducible, zero means seed from current time
+	Seed   int64
+	Logger *zap.Logger
+}
+
+// StressFailure is input on which solution disagrees with reference
+type StressFailure struct {
+	Input string `json:"input"`
+	// Expected is result of reference solution
+	Expected string      `json:"expected"`
+	Result   *TestResult `json:"result"`
+}
+
+// StressResult is result of stress run, Failure is smallest input solution failed on
+type StressResult struct {
+	Seed       int64          `json:"seed"`
+	Iterations int            `json:"iterations"`
+	Failures   int            `json:"failures"`
+	Failure    *StressFailure `json:"failure,omitempty"`
+}
+
+// Stress runs solution and reference solution on random inputs. Inputs grow
+// with iterations and once solution fails only inputs smaller than failed one
+// are generated, so smallest input solution fails on is reported
+func (p *Problem) Stress(opts StressOptions) (*StressResult, error) {
+	if len(p.Signature.Args) == 0 {
+		return nil, ErrNoSignatureArgs
+	}
+	refLang, reference, err := p.reference(opts.ReferenceLang, opts.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = DefaultStressIterations
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.With(zap.String("problem", p.ID), zap.Int64("seed", opts.Seed))
+	generator, err := stress.New(p.Signature.Args, p.Generator, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	type scaledInput struct {
+		input string
+		scale float64
+	}
+	result := &StressResult{Seed: opts.Seed}
+	var mu sync.Mutex
+	var runErr error
+	// maxScale shrinks to scale smallest failed input was generated with
+	maxScale := 1.0
+	inputs := make(chan scaledInput)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for in := range inputs {
+				failure, err := p.stressInput(in.input, refLang, reference, opts, logger)
+				mu.Lock()
+				result.Iterations++
+				switch {
+				case err != nil:
+					if runErr == nil {
+						runErr = err
+					}
+				case failure != nil:
+					result.Failures++
+					if result.Failure == nil || len(in.input) < len(result.Failure.Input) {
+						result.Failure = failure
+						maxScale = in.scale
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+	for i := 0; i < opts.Iterations; i++ {
+		if opts.Duration > 0 && time.Since(start) > opts.Duration {
+			break
+		}
+		mu.Lock()
+		// every other input would fail to compile the same way
+		stop := runErr != nil || (result.Failure != nil && result.Failure.Result.Verdict == execution.VerdictCompilationError)
+		scale := float64(i+1) / float64(opts.Iterations)
+		if scale > maxScale {
+			scale = maxScale
+		}
+		mu.Unlock()
+		if stop {
+			break
+		}
+		inputs <- scaledInput{input: generator.Input(scale), scale: scale}
+	}
+	close(inputs)
+	wg.Wait()
+	if runErr != nil {
+		return nil, runErr
+	}
+	logger.Info("stressed solution",
+		zap.String("lang", opts.Lang),
+		zap.Int("iterations", result.Iterations),
+		zap.Int("failures", result.Failures),
+	)
+	return result, nil
+}
+
+// reference returns reference solution in preferred language or in language of solution
+func (p *Problem) reference(preferred, lang string) (string, string, error) {
+	if preferred != "" {
+		reference, ok := p.References[preferred]
+		if !ok {
+			return "", "", ErrNoReferences
+		}
+		return preferred, reference, nil
+	}
+	if reference, ok := p.References[lang]; ok {
+		return lang, reference, nil
+	}
+	for refLang, reference := range p.References {
+		return refLang, reference, nil
+	}
+	return "", "", ErrNoReferences
+}
+
+// stressInput runs reference and solution on input, failure is nil when they agree
+func (p *Problem) stressInput(input, refLang, reference string, opts StressOptions, logger *zap.Logger) (*StressFailure, error) {
+	expected, err := p.execute(input, refLang, reference, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	if expected.Verdict != execution.VerdictOK {
+		return nil, fmt.Errorf("%v with %v on input:\n%v", ErrReferenceFailed, expected.Verdict, input)
+	}
+	test := &Test{Name: "stress", Input: input, Expected: expected.Result}
+	actual, err := p.runTest(test, opts.Lang, opts.Solution, opts.Flags, logger)
+	if err != nil {
+		return nil, err
+	}
+	if actual.Passed {
+		return nil, nil
+	}
+	return &StressFailure{Input: input, Expected: test.Expected, Result: actual}, nil
+}
@@ -0,0 +1,146 @@
+package problem
+
+import (
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/runner"
+	"go.uber.org/zap"
+)
+
+// Verdicts of submission, execution verdicts are used when solution fails to run
+const (
+	VerdictAccepted    = "Accepted"
+	VerdictWrongAnswer = "WrongAnswer"
+)
+
+// TestResult is result of running solution on single test
+type TestResult struct {
+	Index   int    `json:"index"`
+	Hidden  bool   `json:"hidden"`
+	Test    *Test  `json:"test,omitempty"`
+	Verdict string `json:"verdict"`
+	Passed  bool   `json:"passed"`
+	// Message is explanation of verdict given by checker program
+	Message string                `json:"message,omitempty"`
+	Result  *execution.CodeResult `json:"result,omitempty"`
+}
+
+// SubmissionResult is result of running solution on problem tests
+type SubmissionResult struct {
+	Verdict string `json:"verdict"`
+	Passed  int    `json:"passed"`
+	Total   int    `json:"total"`
+	// FirstFailed is index of first failed test, -1 when all tests passed
+	FirstFailed int           `json:"firstFailed"`
+	Tests       []*TestResult `json:"tests"`
+}
+
+// Redact returns copy of submission result without inputs, expected and
+// actual outputs of hidden tests so it can be returned to candidate
+func (s *SubmissionResult) Redact() *SubmissionResult {
+	result := *s
+	result.Tests = nil
+	for _, test := range s.Tests {
+		if test.Hidden {
+			test = &TestResult{
+				Index:   test.Index,
+				Hidden:  true,
+				Verdict: test.Verdict,
+				Passed:  test.Passed,
+			}
+		}
+		result.Tests = append(result.Tests, test)
+	}
+	return &result
+}
+
+// Run runs solution on tests which are visible to candidate, flags are
+// requested flags of language, flags and logger are optional
+func (p *Problem) Run(lang, solution string, flags *execution.Flags, logger *zap.Logger) (*SubmissionResult, error) {
+	return p.run(p.Examples(), lang, solution, flags, logger)
+}
+
+// Submit runs solution on every test of problem, including hidden ones, flags
+// are requested flags of language, flags and logger are optional
+func (p *Problem) Submit(lang, solution string, flags *execution.Flags, logger *zap.Logger) (*SubmissionResult, error) {
+	return p.run(p.Tests, lang, solution, flags, logger)
+}
+
+func (p *Problem) run(tests []*Test, lang, solution string, flags *execution.Flags, logger *zap.Logger) (*SubmissionResult, error) {
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.With(zap.String("problem", p.ID))
+	submission := &SubmissionResult{
+		Verdict:     VerdictAccepted,
+		Total:       len(tests),
+		FirstFailed: -1,
+	}
+	for i, test := range tests {
+		testResult, err := p.runTest(test, lang, solution, flags, logger.With(zap.String("test", test.Name)))
+		if err != nil {
+			return nil, err
+		}
+		testResult.Index = i
+		submission.Tests = append(submission.Tests, testResult)
+		if testResult.Passed {
+			submission.Passed++
+			continue
+		}
+		if submission.FirstFailed == -1 {
+			submission.FirstFailed = i
+			submission.Verdict = testResult.Verdict
+		}
+		// every other test would fail to compile the same way
+		if testResult.Verdict == execution.VerdictCompilationError {
+			break
+		}
+	}
+	logger.Info("judged solution",
+		zap.String("verdict", submission.Verdict),
+		zap.Int("passed", submission.Passed),
+		zap.Int("total", submission.Total),
+	)
+	return submission, nil
+}
+
+// execute runs solution on input with time limit of problem
+func (p *Problem) execute(input, lang, solution string, flags *execution.Flags, logger *zap.Logger) (*execution.CodeResult, error) {
+	return runner.Run(&runner.Request{
+		Lang:      lang,
+		Solution:  solution,
+		Input:     input,
+		Returns:   p.Signature.Returns,
+		TimeLimit: time.Duration(p.TimeLimit) * time.Millisecond,
+		Flags:     flags,
+		Logger:    logger,
+	})
+}
+
+func (p *Problem) runTest(test *Test, lang, solution string, flags *execution.Flags, logger *zap.Logger) (*TestResult, error) {
+	result, err := p.execute(test.Input, lang, solution, flags, logger)
+	if err != nil {
+		return nil, err
+	}
+	testResult := &TestResult{
+		Hidden:  test.Hidden,
+		Test:    test,
+		Verdict: result.Verdict,
+		Result:  result,
+	}
+	if result.Verdict != execution.VerdictOK {
+		return testResult, nil
+	}
+	passed, message, err := p.Checker.Check(test.Input, test.Expected, result.Result, p.Signature.Returns, logger)
+	if err != nil {
+		return nil, err
+	}
+	testResult.Passed = passed
+	testResult.Message = message
+	testResult.Verdict = VerdictAccepted
+	if !passed {
+		testResult.Verdict = VerdictWrongAnswer
+	}
+	return testResult, nil
+}
@@ -0,0 +1,109 @@
+package problem
+
+import (
+	"errors"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// ErrNoReferences is returned when problem has no reference solutions to verify it with
+var ErrNoReferences = errors.New("Problem has no reference solutions")
+
+// Issues found while verifying test
+const (
+	// IssueExpected means every reference agrees on result which differs from expected output
+	IssueExpected = "expected output differs from every reference"
+	// IssueDisagree means references in different languages return different results
+	IssueDisagree = "references disagree"
+	// IssueFailed means every reference failed to compile or run
+	IssueFailed = "every reference failed to run"
+)
+
+// CaseVerification holds results of every reference solution on single test
+type CaseVerification struct {
+	Test    *Test                  `json:"test"`
+	Results map[string]*TestResult `json:"results"`
+	// Issue is empty when every reference passed
+	Issue string `json:"issue,omitempty"`
+}
+
+// Verification is result of running reference solutions on every test of problem
+type Verification struct {
+	Langs []string            `json:"langs"`
+	Cases []*CaseVerification `json:"cases"`
+}
+
+// Failed reports whether any test has issue
+func (v *Verification) Failed() bool {
+	for _, c := range v.Cases {
+		if c.Issue != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify runs reference solutions in langs on every test, all references are
+// run when langs is empty. Logger is optional
+func (p *Problem) Verify(langs []string, logger *zap.Logger) (*Verification, error) {
+	if len(langs) == 0 {
+		for lang := range p.References {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+	}
+	if len(langs) == 0 {
+		return nil, ErrNoReferences
+	}
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.With(zap.String("problem", p.ID))
+
+	verification := &Verification{Langs: langs}
+	for _, test := range p.Tests {
+		c := &CaseVerification{Test: test, Results: make(map[string]*TestResult)}
+		for _, lang := range langs {
+			reference, ok := p.References[lang]
+			if !ok {
+				return nil, ErrNoReferences
+			}
+			result, err := p.runTest(test, lang, reference, nil, logger.With(zap.String("test", test.Name)))
+			if err != nil {
+				return nil, err
+			}
+			c.Results[lang] = result
+		}
+		c.Issue = p.issue(c, langs)
+		verification.Cases = append(verification.Cases, c)
+	}
+	return verification, nil
+}
+
+// issue tells whether references are wrong or expected output is
+func (p *Problem) issue(c *CaseVerification, langs []string) string {
+	first := c.Results[langs[0]]
+	agree := first.Verdict == VerdictAccepted || first.Verdict == VerdictWrongAnswer
+	for _, lang := range langs {
+		result := c.Results[lang]
+		if result.Verdict != first.Verdict {
+			return IssueDisagree
+		}
+		// checker program accepts many answers, each was already checked against expected one
+		if !agree || p.Checker.Type == CheckerProgram {
+			continue
+		}
+		same, _, err := p.Checker.Check(c.Test.Input, first.Result.Result, result.Result.Result, p.Signature.Returns, nil)
+		if err != nil || !same {
+			return IssueDisagree
+		}
+	}
+	switch {
+	case first.Verdict == VerdictAccepted:
+		return ""
+	case first.Verdict == VerdictWrongAnswer:
+		return IssueExpected
+	}
+	return IssueFailed
+}
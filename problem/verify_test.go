@@ -0,0 +1,127 @@
+package problem
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/Strovala/crackview/execution"
+	"go.uber.org/zap"
+)
+
+// TestMain runs tests from repository root where templates are
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func requirePython(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not available")
+	}
+}
+
+// sumProblem returns problem whose reference returns sum of arguments and
+// crashes when it is zero, second test has wrong expected output
+func sumProblem() *Problem {
+	return &Problem{
+		ID:         "sum",
+		Signature:  Signature{Args: []string{"int", "int"}, Returns: "int"},
+		References: map[string]string{execution.Python: "class Solution:\n    def code(a, b):\n        return (a + b) // (a + b) * (a + b)\n"},
+		Tests: []*Test{
+			{Name: "1", Input: "2 => int\n3 => int", Expected: "5"},
+			{Name: "2", Input: "1 => int\n1 => int", Expected: "3", Hidden: true},
+			{Name: "3", Input: "0 => int\n0 => int", Expected: "0", Hidden: true},
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	requirePython(t)
+	verification, err := sumProblem().Verify(nil, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verification.Langs) != 1 || verification.Langs[0] != execution.Python {
+		t.Errorf("langs = %v, want every reference", verification.Langs)
+	}
+	want := []string{"", IssueExpected, IssueFailed}
+	if len(verification.Cases) != len(want) {
+		t.Fatalf("verified %v tests, want %v", len(verification.Cases), len(want))
+	}
+	for i, c := range verification.Cases {
+		if c.Issue != want[i] {
+			t.Errorf("test %v issue = %q, want %q", c.Test.Name, c.Issue, want[i])
+		}
+	}
+	if !verification.Failed() {
+		t.Error("verification with issues did not fail")
+	}
+}
+
+func TestVerifyWithoutReferences(t *testing.T) {
+	p := sumProblem()
+	if _, err := p.Verify([]string{execution.Java}, zap.NewNop()); err != ErrNoReferences {
+		t.Errorf("Verify(java) error = %v, want %v", err, ErrNoReferences)
+	}
+	p.References = nil
+	if _, err := p.Verify(nil, zap.NewNop()); err != ErrNoReferences {
+		t.Errorf("Verify() error = %v, want %v", err, ErrNoReferences)
+	}
+}
+
+func TestIssue(t *testing.T) {
+	// result returns result of reference with verdict and value
+	result := func(verdict, value string) *TestResult {
+		return &TestResult{Verdict: verdict, Result: &execution.CodeResult{Result: value}}
+	}
+	tests := []struct {
+		name    string
+		checker Checker
+		results map[string]*TestResult
+		want    string
+	}{
+		{
+			name:    "accepted",
+			results: map[string]*TestResult{"a": result(VerdictAccepted, "[1, 2]"), "b": result(VerdictAccepted, "[1, 2]")},
+			want:    "",
+		},
+		{
+			name:    "wrong expected output",
+			results: map[string]*TestResult{"a": result(VerdictWrongAnswer, "[1, 2]"), "b": result(VerdictWrongAnswer, "[1, 2]")},
+			want:    IssueExpected,
+		},
+		{
+			name:    "different wrong answers",
+			results: map[string]*TestResult{"a": result(VerdictWrongAnswer, "[1, 2]"), "b": result(VerdictWrongAnswer, "[2, 3]")},
+			want:    IssueDisagree,
+		},
+		{
+			name:    "different verdicts",
+			results: map[string]*TestResult{"a": result(VerdictAccepted, "[1, 2]"), "b": result(execution.VerdictRuntimeError, "")},
+			want:    IssueDisagree,
+		},
+		{
+			name:    "every reference failed",
+			results: map[string]*TestResult{"a": result(execution.VerdictTimeLimit, ""), "b": result(execution.VerdictTimeLimit, "")},
+			want:    IssueFailed,
+		},
+		{
+			name:    "checker accepts both",
+			checker: Checker{Type: CheckerUnordered},
+			results: map[string]*TestResult{"a": result(VerdictWrongAnswer, "[1, 2]"), "b": result(VerdictWrongAnswer, "[2, 1]")},
+			want:    IssueExpected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Problem{Signature: Signature{Returns: "[]int"}, Checker: tt.checker}
+			c := &CaseVerification{Test: &Test{Input: "[1, 2] => []int"}, Results: tt.results}
+			if got := p.issue(c, []string{"a", "b"}); got != tt.want {
+				t.Errorf("issue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+class Solution {
+public:
+    static vector<int> code(vector<int> nums, int target) {
+        map<int, int> seen;
+        for (int i = 0; i < (int)nums.size(); i++) {
+            auto it = seen.find(target - nums[i]);
+            if (it != seen.end()) {
+                return {it->second, i};
+            }
+            seen[nums[i]] = i;
+        }
+        return {};
+    }
+};
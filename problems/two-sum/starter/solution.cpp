@@ -0,0 +1,6 @@
+class Solution {
+public:
+    static vector<int> code(vector<int> nums, int target) {
+        return {};
+    }
+};
@@ -0,0 +1,5 @@
+class Solution {
+    public static int[] code(int[] nums, int target) {
+        return new int[]{};
+    }
+}
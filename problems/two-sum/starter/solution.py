@@ -0,0 +1,3 @@
+class Solution:
+    def code(nums, target):
+        pass
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// maxIdle is number of clients after which idle clients are forgotten
+const maxIdle = 10000
+
+// Bucket is token bucket config, Rate is number of tokens refilled per second
+// and Burst is bucket capacity. Zero rate disables limiting
+type Bucket struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// Config is rate limiting config, Concurrency caps running executions per client
+type Config struct {
+	Key         Bucket `mapstructure:"key"`
+	IP          Bucket `mapstructure:"ip"`
+	Concurrency int    `mapstructure:"concurrency"`
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter is token bucket rate limiter per client key
+type Limiter struct {
+	config  Bucket
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// NewLimiter creates limiter with given bucket config
+func NewLimiter(config Bucket) *Limiter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	return &Limiter{config: config, buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// Allow takes token from client bucket, when bucket is empty it returns
+// how long client should wait for next token
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil || l.config.Rate <= 0 {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		l.prune(now)
+		b = &bucket{tokens: float64(l.config.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.config.Burst), b.tokens+now.Sub(b.last).Seconds()*l.config.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / l.config.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// prune forgets clients whose buckets are refilled when there are too many clients
+func (l *Limiter) prune(now time.Time) {
+	if len(l.buckets) < maxIdle {
+		return
+	}
+	full := float64(l.config.Burst) / l.config.Rate
+	for key, b := range l.buckets {
+		if now.Sub(b.last).Seconds() >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Semaphore caps number of concurrent operations per client key
+type Semaphore struct {
+	limit   int
+	mu      sync.Mutex
+	running map[string]int
+}
+
+// NewSemaphore creates semaphore allowing limit operations per client, zero limit disables it
+func NewSemaphore(limit int) *Semaphore {
+	return &Semaphore{limit: limit, running: map[string]int{}}
+}
+
+// Acquire reserves operation slot for client, release must be called when operation ends
+func (s *Semaphore) Acquire(key string) (release func(), ok bool) {
+	if s == nil || s.limit <= 0 {
+		return func() {}, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[key] >= s.limit {
+		return nil, false
+	}
+	s.running[key]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.running[key]--; s.running[key] <= 0 {
+				delete(s.running, key)
+			}
+		})
+	}, true
+}
+
+// AcquireUpTo reserves as many of n operation slots for client as are free,
+// release frees all of them. Semaphore which is disabled grants all n slots
+func (s *Semaphore) AcquireUpTo(key string, n int) (acquired int, release func()) {
+	var releases []func()
+	for ; acquired < n; acquired++ {
+		r, ok := s.Acquire(key)
+		if !ok {
+			break
+		}
+		releases = append(releases, r)
+	}
+	return acquired, func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+}
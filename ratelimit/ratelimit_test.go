@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	type step struct {
+		// advance moves clock before request
+		advance time.Duration
+		key     string
+		allowed bool
+		wait    time.Duration
+	}
+	tests := []struct {
+		name   string
+		config Bucket
+		steps  []step
+	}{
+		{
+			name:   "burst then wait",
+			config: Bucket{Rate: 2, Burst: 2},
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", wait: 500 * time.Millisecond},
+				{advance: 250 * time.Millisecond, key: "a", wait: 250 * time.Millisecond},
+				{advance: 250 * time.Millisecond, key: "a", allowed: true},
+				{key: "a", wait: 500 * time.Millisecond},
+			},
+		},
+		{
+			name:   "refill is capped by burst",
+			config: Bucket{Rate: 1, Burst: 2},
+			steps: []step{
+				{key: "a", allowed: true},
+				{advance: time.Hour, key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", wait: time.Second},
+			},
+		},
+		{
+			name:   "clients have own buckets",
+			config: Bucket{Rate: 1, Burst: 1},
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", wait: time.Second},
+				{key: "b", allowed: true},
+			},
+		},
+		{
+			name:   "zero burst allows one",
+			config: Bucket{Rate: 1},
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", wait: time.Second},
+			},
+		},
+		{
+			name:   "zero rate disables limit",
+			config: Bucket{Burst: 1},
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			l := NewLimiter(tt.config)
+			l.now = func() time.Time { return now }
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				allowed, wait := l.Allow(s.key)
+				if allowed != s.allowed || wait != s.wait {
+					t.Errorf("step %v: Allow(%q) = %v, %v, want %v, %v", i, s.key, allowed, wait, s.allowed, s.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterNil(t *testing.T) {
+	var l *Limiter
+	if allowed, _ := l.Allow("a"); !allowed {
+		t.Error("nil limiter denied request")
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(2)
+	releaseA, ok := s.Acquire("a")
+	if !ok {
+		t.Fatal("first acquire denied")
+	}
+	if _, ok := s.Acquire("a"); !ok {
+		t.Fatal("second acquire denied")
+	}
+	if _, ok := s.Acquire("a"); ok {
+		t.Fatal("acquire over limit allowed")
+	}
+	if _, ok := s.Acquire("b"); !ok {
+		t.Fatal("acquire of other client denied")
+	}
+	releaseA()
+	// release is idempotent
+	releaseA()
+	if _, ok := s.Acquire("a"); !ok {
+		t.Fatal("acquire after release denied")
+	}
+	if _, ok := s.Acquire("a"); ok {
+		t.Fatal("double release freed two slots")
+	}
+}
+
+func TestSemaphoreAcquireUpTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		held  int
+		n     int
+		want  int
+	}{
+		{name: "all free", limit: 4, n: 3, want: 3},
+		{name: "some free", limit: 4, held: 2, n: 3, want: 2},
+		{name: "none free", limit: 2, held: 2, n: 3, want: 0},
+		{name: "disabled", limit: 0, held: 2, n: 3, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSemaphore(tt.limit)
+			for i := 0; i < tt.held; i++ {
+				s.Acquire("a")
+			}
+			got, release := s.AcquireUpTo("a", tt.n)
+			if got != tt.want {
+				t.Fatalf("AcquireUpTo = %v, want %v", got, tt.want)
+			}
+			release()
+			if tt.limit > 0 && s.running["a"] != tt.held {
+				t.Errorf("running after release = %v, want %v", s.running["a"], tt.held)
+			}
+		})
+	}
+}
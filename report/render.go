@@ -0,0 +1,124 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/Strovala/crackview/store"
+)
+
+// Formats report can be rendered in
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+const (
+	templatesPath    = "report/templates"
+	htmlTemplateName = "report.html"
+	mdTemplateName   = "report.md"
+)
+
+// ErrUnknownFormat is returned when report is rendered in unsupported format
+var ErrUnknownFormat = errors.New("Unknown report format")
+
+// PassRate is share of tests solution passed in single run or submission
+type PassRate struct {
+	Time      time.Time     `json:"time"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Kind      string        `json:"kind"`
+	ProblemID string        `json:"problemId"`
+	Verdict   string        `json:"verdict"`
+	Passed    int           `json:"passed"`
+	Total     int           `json:"total"`
+}
+
+// Percent returns pass rate in percents
+func (p *PassRate) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Passed * 100 / p.Total
+}
+
+// PassRates returns pass rates of runs and submissions in order they were
+// made, elapsed time is counted from session start
+func (r *Report) PassRates() []*PassRate {
+	var result []*PassRate
+	for _, e := range r.Executions {
+		if e.Submission == nil {
+			continue
+		}
+		result = append(result, &PassRate{
+			Time:      e.CreatedAt,
+			Elapsed:   r.elapsed(e.CreatedAt),
+			Kind:      e.Kind,
+			ProblemID: e.ProblemID,
+			Verdict:   e.Verdict,
+			Passed:    e.Submission.Passed,
+			Total:     e.Submission.Total,
+		})
+	}
+	return result
+}
+
+func (r *Report) elapsed(t time.Time) time.Duration {
+	start := r.Session.CreatedAt
+	if r.Session.StartedAt != nil {
+		start = *r.Session.StartedAt
+	}
+	return t.Sub(start).Truncate(time.Second)
+}
+
+var funcs = map[string]interface{}{
+	"time": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04:05")
+	},
+	"elapsed": func(r *Report, t time.Time) string {
+		return r.elapsed(t).String()
+	},
+	// timings returns compile and run time of execution in milliseconds,
+	// runs against problem tests are summed
+	"timings": func(e *store.Execution) string {
+		if e.Result != nil {
+			return fmt.Sprintf("%vms / %vms", e.Result.CompileTime, e.Result.RunTime)
+		}
+		var compileTime, runTime int64
+		if e.Submission != nil {
+			for _, test := range e.Submission.Tests {
+				if test.Result != nil {
+					compileTime += test.Result.CompileTime
+					runTime += test.Result.RunTime
+				}
+			}
+		}
+		return fmt.Sprintf("%vms / %vms", compileTime, runTime)
+	},
+	"inc": func(i int) int {
+		return i + 1
+	},
+}
+
+// Render writes report in given format to w
+func (r *Report) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatHTML:
+		t, err := htmlTemplate.New(htmlTemplateName).Funcs(funcs).ParseFiles(filepath.Join(templatesPath, htmlTemplateName))
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	case FormatMarkdown:
+		t, err := template.New(mdTemplateName).Funcs(funcs).ParseFiles(filepath.Join(templatesPath, mdTemplateName))
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, r)
+	}
+	return ErrUnknownFormat
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"time"
+
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
+)
+
+// ProblemResult is result of last submission of session for problem
+type ProblemResult struct {
+	ProblemID string `json:"problemId"`
+	Title     string `json:"title"`
+	Submitted bool   `json:"submitted"`
+	Verdict   string `json:"verdict,omitempty"`
+	Passed    int    `json:"passed"`
+	Total     int    `json:"total"`
+}
+
+// Report is everything that happened in interview session in one place
+type Report struct {
+	Session   *session.Session   `json:"session"`
+	Problems  []*problem.Problem `json:"problems"`
+	Rubric    session.Rubric     `json:"rubric"`
+	Scorecard *session.Scorecard `json:"scorecard"`
+	Notes     []*session.Note    `json:"notes"`
+	// Lang and Code are final code of session
+	Lang        string             `json:"lang"`
+	Code        string             `json:"code"`
+	Results     []*ProblemResult   `json:"results"`
+	Executions  []*store.Execution `json:"executions"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+}
+
+// Build collects report of session from store, problems which are no longer
+// in bank are left out
+func Build(s *store.Store, bank *problem.Bank, rubric session.Rubric, sessionID string) (*Report, error) {
+	sess, err := s.Session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		Session:     sess.ForRole(""),
+		Rubric:      rubric,
+		GeneratedAt: time.Now(),
+	}
+	if report.Scorecard, err = s.Scorecard(sessionID); err != nil {
+		return nil, err
+	}
+	if report.Notes, err = s.Notes(sessionID); err != nil {
+		return nil, err
+	}
+	if report.Executions, err = s.Executions(store.ExecutionFilter{SessionID: sessionID}); err != nil {
+		return nil, err
+	}
+
+	doc, err := s.LoadDocument(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	report.Lang, report.Code = doc.Lang, doc.Text
+	// sessions without shared document send code with every execution
+	if report.Code == "" && len(report.Executions) > 0 {
+		last := report.Executions[len(report.Executions)-1]
+		report.Lang, report.Code = last.Lang, last.Code
+	}
+
+	for _, id := range sess.ProblemIDs {
+		p, err := bank.Get(id)
+		if err != nil {
+			continue
+		}
+		report.Problems = append(report.Problems, p)
+		report.Results = append(report.Results, lastResult(p, report.Executions))
+	}
+	return report, nil
+}
+
+// lastResult returns result of last submission for problem
+func lastResult(p *problem.Problem, executions []*store.Execution) *ProblemResult {
+	result := &ProblemResult{ProblemID: p.ID, Title: p.Title}
+	for _, e := range executions {
+		if e.Kind != store.KindSubmit || e.ProblemID != p.ID || e.Submission == nil {
+			continue
+		}
+		result.Submitted = true
+		result.Verdict = e.Submission.Verdict
+		result.Passed = e.Submission.Passed
+		result.Total = e.Submission.Total
+	}
+	return result
+}
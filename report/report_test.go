@@ -0,0 +1,238 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+	"github.com/Strovala/crackview/session"
+	"github.com/Strovala/crackview/store"
+)
+
+// TestMain runs tests from repository root where templates and problems are
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func openTestStore(t *testing.T) *store.Store {
+	dir, err := ioutil.TempDir("", "crackview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.Open(filepath.Join(dir, "crackview.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(dir)
+	})
+	return s
+}
+
+// buildTestReport saves session with notes, scorecard and executions of two-sum
+// and builds its report
+func buildTestReport(t *testing.T) *Report {
+	s := openTestStore(t)
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := session.New("Screen", "Ann", "Bob", []string{"two-sum", "removed"}, 45)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	sess.StartedAt = &started
+	if err := s.SaveSession(sess); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddNote(sess.ID, &session.Note{Time: started.Add(time.Minute), Author: "Ann", Text: "asked about <duplicates>"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveScorecard(sess.ID, &session.Scorecard{Scores: map[string]int{"communication": 3}, Summary: "Solid"}); err != nil {
+		t.Fatal(err)
+	}
+	executions := []*store.Execution{
+		{
+			Kind: store.KindExecute, SessionID: sess.ID, Lang: execution.Python, Code: "print(1)", Verdict: execution.VerdictOK,
+			Result: &execution.CodeResult{CompileTime: 0, RunTime: 20}, CreatedAt: started.Add(2 * time.Minute),
+		},
+		{
+			Kind: store.KindSubmit, SessionID: sess.ID, ProblemID: "two-sum", Lang: execution.Python, Code: "wrong", Verdict: problem.VerdictWrongAnswer,
+			Submission: &problem.SubmissionResult{Verdict: problem.VerdictWrongAnswer, Passed: 1, Total: 4, Tests: []*problem.TestResult{
+				{Result: &execution.CodeResult{CompileTime: 5, RunTime: 10}},
+				{Result: &execution.CodeResult{CompileTime: 5, RunTime: 30}},
+			}},
+			CreatedAt: started.Add(5 * time.Minute),
+		},
+		{
+			Kind: store.KindSubmit, SessionID: sess.ID, ProblemID: "two-sum", Lang: execution.Python, Code: "if a < b: pass", Verdict: problem.VerdictAccepted,
+			Submission: &problem.SubmissionResult{Verdict: problem.VerdictAccepted, Passed: 4, Total: 4},
+			CreatedAt:  started.Add(10 * time.Minute),
+		},
+		{Kind: store.KindExecute, SessionID: "other", Lang: execution.Java, Code: "other", CreatedAt: started},
+	}
+	for _, e := range executions {
+		if err := s.SaveExecution(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := Build(s, bank, session.DefaultRubric, sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestBuild(t *testing.T) {
+	report := buildTestReport(t)
+	if report.Session.InterviewerToken != "" || report.Session.CandidateToken != "" {
+		t.Errorf("report session has tokens: %+v", report.Session)
+	}
+	if len(report.Executions) != 3 {
+		t.Fatalf("report has %v executions, want 3 of session", len(report.Executions))
+	}
+	if report.Lang != execution.Python || report.Code != "if a < b: pass" {
+		t.Errorf("final code = %v %q, want code of last execution", report.Lang, report.Code)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].ID != "two-sum" {
+		t.Fatalf("report problems = %v, want only two-sum", report.Problems)
+	}
+	want := []*ProblemResult{{ProblemID: "two-sum", Title: report.Problems[0].Title, Submitted: true, Verdict: problem.VerdictAccepted, Passed: 4, Total: 4}}
+	if !reflect.DeepEqual(report.Results, want) {
+		t.Errorf("results = %+v, want %+v", report.Results[0], want[0])
+	}
+	if len(report.Notes) != 1 || report.Scorecard.Summary != "Solid" {
+		t.Errorf("notes = %v, scorecard = %+v, want saved ones", report.Notes, report.Scorecard)
+	}
+}
+
+func TestBuildMissingSession(t *testing.T) {
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Build(openTestStore(t), bank, session.DefaultRubric, "missing"); err != store.ErrNotFound {
+		t.Errorf("Build() error = %v, want %v", err, store.ErrNotFound)
+	}
+}
+
+func TestPassRates(t *testing.T) {
+	report := buildTestReport(t)
+	rates := report.PassRates()
+	if len(rates) != 2 {
+		t.Fatalf("pass rates = %v, want one per submission", len(rates))
+	}
+	tests := []struct {
+		elapsed time.Duration
+		verdict string
+		percent int
+	}{
+		{elapsed: 5 * time.Minute, verdict: problem.VerdictWrongAnswer, percent: 25},
+		{elapsed: 10 * time.Minute, verdict: problem.VerdictAccepted, percent: 100},
+	}
+	for i, tt := range tests {
+		if rates[i].Elapsed != tt.elapsed || rates[i].Verdict != tt.verdict || rates[i].Percent() != tt.percent {
+			t.Errorf("pass rate %v = %+v with %v%%, want %v %v with %v%%", i, rates[i], rates[i].Percent(), tt.elapsed, tt.verdict, tt.percent)
+		}
+	}
+	if percent := (&PassRate{}).Percent(); percent != 0 {
+		t.Errorf("percent without tests = %v, want 0", percent)
+	}
+}
+
+func TestRender(t *testing.T) {
+	report := buildTestReport(t)
+	tests := []struct {
+		format   string
+		want     []string
+		excluded []string
+	}{
+		{
+			format: FormatMarkdown,
+			want: []string{
+				"# Interview report: Screen",
+				"| communication | 3/4 |",
+				"| problem solving | -/4 |",
+				"asked about <duplicates>",
+				"```python\nif a < b: pass\n```",
+				"| 5m0s | two-sum | submit | WrongAnswer | 1/4 | 25% |",
+				"| 10ms / 40ms |",
+				"| 0ms / 20ms |",
+			},
+		},
+		{
+			format: FormatHTML,
+			want: []string{
+				"<h1>Interview report: Screen</h1>",
+				"asked about &lt;duplicates&gt;",
+				"<pre><code>if a &lt; b: pass</code></pre>",
+				"width: 25%",
+			},
+			excluded: []string{"<duplicates>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := report.Render(&out, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("report does not contain %q:\n%v", want, out.String())
+				}
+			}
+			for _, excluded := range tt.excluded {
+				if strings.Contains(out.String(), excluded) {
+					t.Errorf("report contains %q", excluded)
+				}
+			}
+		})
+	}
+	if err := report.Render(ioutil.Discard, "pdf"); err != ErrUnknownFormat {
+		t.Errorf("Render() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestRenderEmptySession(t *testing.T) {
+	s := openTestStore(t)
+	bank, err := problem.LoadBank("problems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess, err := session.New("", "Ann", "Bob", []string{"two-sum"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveSession(sess); err != nil {
+		t.Fatal(err)
+	}
+	report, err := Build(s, bank, session.DefaultRubric, sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{FormatMarkdown, FormatHTML} {
+		var out bytes.Buffer
+		if err := report.Render(&out, format); err != nil {
+			t.Fatalf("Render(%v) error = %v", format, err)
+		}
+		for _, want := range []string{"not submitted", "No notes."} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%v report does not contain %q", format, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/generator"
+	"github.com/Strovala/crackview/metrics"
+	"github.com/Strovala/crackview/parser"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// DefaultReturns is type of value returned by solution when request does not specify it
+const DefaultReturns = "int"
+
+var (
+	cacheOnce sync.Once
+	cache     *execution.Cache
+	poolOnce  sync.Once
+	pool      *execution.JVMPool
+)
+
+// Cache returns compile cache configured by compileCache config, it is nil
+// when cache is disabled or can not be created
+func Cache() *execution.Cache {
+	cacheOnce.Do(func() {
+		if !viper.GetBool("compileCache.enabled") {
+			return
+		}
+		dir := viper.GetString("compileCache.dir")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "crackview-cache")
+		}
+		var err error
+		cache, err = execution.NewCache(dir, viper.GetInt64("compileCache.maxSize")<<20)
+		if err != nil {
+			zap.L().Warn("unable to create compile cache", zap.String("dir", dir), zap.Error(err))
+		}
+	})
+	return cache
+}
+
+// JVMPool returns pool of warm JVMs configured by jvmPool config, it is nil
+// when pool is disabled or worker can not be compiled
+func JVMPool() *execution.JVMPool {
+	poolOnce.Do(func() {
+		if !viper.GetBool("jvmPool.enabled") {
+			return
+		}
+		flags, err := LanguageFlags(execution.Java)
+		if err != nil {
+			zap.L().Warn("unable to read java flags, java code runs on new JVM", zap.Error(err))
+			return
+		}
+		dir := filepath.Join(os.TempDir(), "crackview-jvm")
+		pool, err = execution.NewJVMPool(dir, viper.GetInt("jvmPool.size"), viper.GetInt("jvmPool.maxRuns"), flags.Run)
+		if err != nil {
+			zap.L().Warn("unable to create JVM pool, java code runs on new JVM", zap.Error(err))
+		}
+	})
+	return pool
+}
+
+// CloseJVMPool stops warm JVMs of pool, pool is not created when it was not
+// used and it is not created after it was closed
+func CloseJVMPool() {
+	poolOnce.Do(func() {})
+	if pool != nil {
+		pool.Close()
+	}
+}
+
+// LanguageFlags returns flags of language from flags config
+func LanguageFlags(lang string) (*execution.LanguageFlags, error) {
+	flags := &execution.LanguageFlags{}
+	if err := viper.UnmarshalKey("flags."+lang, flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// SelectFlags returns flags code of language runs with, requested flags have
+// to be allowed by flags config
+func SelectFlags(lang string, requested *execution.Flags) (execution.Flags, error) {
+	flags, err := LanguageFlags(lang)
+	if err != nil {
+		return execution.Flags{}, err
+	}
+	return flags.Select(requested)
+}
+
+// newExecutor creates executor of language, java code runs on warm JVM when
+// pool is enabled, request does not need JVM started with other flags and
+// output is not streamed, pooled JVM passes output only once code finishes
+func newExecutor(lang, dir string, requested *execution.Flags, streamed bool) (execution.Executor, error) {
+	if lang == execution.Java && (requested == nil || len(requested.Run) == 0) && !streamed && JVMPool() != nil {
+		return execution.NewPooledJavaExecutor(dir, JVMPool()), nil
+	}
+	return execution.NewExecutor(lang, dir)
+}
+
+// Request describes single run of solution
+type Request struct {
+	Lang     string
+	Solution string
+	// Input is solution arguments in parser syntax
+	Input string
+	// Returns is type of value returned by solution in parser syntax
+	Returns string
+	// TimeLimit limits how long solution can run, defaults to timeLimit from config
+	TimeLimit time.Duration
+	// Flags are added to flags from config of language, they have to be allowed there
+	Flags *execution.Flags
+	// Listener receives output while code is running, it is optional
+	Listener execution.OutputListener
+	// Context stops run once it is done, it is optional
+	Context context.Context
+	// Logger logs run steps, defaults to global logger
+	Logger *zap.Logger
+}
+
+// InputError is returned when input or returns of request can not be parsed
+type InputError struct {
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("Invalid input: %v", e.Err)
+}
+
+// Validate checks that language of request is supported and that its input
+// and returns can be parsed, it returns ErrUnknownLanguage or InputError
+func Validate(req *Request) error {
+	_, _, err := parse(req)
+	return err
+}
+
+// parse checks language of request and parses its input and returns
+func parse(req *Request) ([]generator.Argument, *generator.Result, error) {
+	if _, ok := execution.MainNames[req.Lang]; !ok {
+		return nil, nil, execution.ErrUnknownLanguage
+	}
+	args, err := parser.Parse(req.Input)
+	if err != nil {
+		metrics.ParseErrors.WithLabelValues(metrics.SourceInput).Inc()
+		return nil, nil, &InputError{Err: err}
+	}
+	returns := req.Returns
+	if returns == "" {
+		returns = DefaultReturns
+	}
+	result, err := parser.ParseType(returns)
+	if err != nil {
+		metrics.ParseErrors.WithLabelValues(metrics.SourceReturns).Inc()
+		return nil, nil, &InputError{Err: err}
+	}
+	return args, result, nil
+}
+
+// Run generates code for solution with request input, executes it in
+// temporary directory and returns its result
+func Run(req *Request) (*execution.CodeResult, error) {
+	logger := req.Logger
+	if logger == nil {
+		logger = zap.L()
+	}
+	logger = logger.With(zap.String("lang", req.Lang))
+	metrics.QueueDepth.Inc()
+	defer metrics.QueueDepth.Dec()
+	args, result, err := parse(req)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir(viper.GetString("workdir"), "crackview")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	start := time.Now()
+	if err := generator.Generate(dir, args, result, req.Lang, req.Solution); err != nil {
+		logger.Warn("unable to generate code", zap.Error(err))
+		return nil, err
+	}
+	logger.Debug("generated code", zap.String("dir", dir), zap.Duration("duration", time.Since(start)))
+	flags, err := SelectFlags(req.Lang, req.Flags)
+	if err != nil {
+		return nil, err
+	}
+	executor, err := newExecutor(req.Lang, dir, req.Flags, req.Listener != nil)
+	if err != nil {
+		return nil, err
+	}
+	executor.SetFlags(flags)
+	if req.Listener != nil {
+		executor.Listen(req.Listener)
+	}
+	timeLimit := req.TimeLimit
+	if timeLimit == 0 {
+		timeLimit = time.Duration(viper.GetInt("timeLimit")) * time.Millisecond
+	}
+	executor.SetTimeLimit(timeLimit)
+	executor.SetLogger(logger)
+	executor.SetCache(Cache())
+	if req.Context != nil {
+		executor.SetContext(req.Context)
+	}
+	codeResult, err := executor.Execute()
+	if err != nil {
+		logger.Error("unable to execute code", zap.Error(err))
+		return nil, err
+	}
+	metrics.ObserveExecution(req.Lang, codeResult.Verdict, codeResult.CompileTime, codeResult.RunTime)
+	logger.Info("executed code",
+		zap.String("verdict", codeResult.Verdict),
+		zap.Int64("compileTime", codeResult.CompileTime),
+		zap.Int64("runTime", codeResult.RunTime),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return codeResult, nil
+}
@@ -0,0 +1,135 @@
+package session
+
+import "errors"
+
+// ErrInvalidEdit is returned when edit does not fit into document
+var ErrInvalidEdit = errors.New("Edit is out of document bounds")
+
+// ErrInvalidRevision is returned when edit is based on unknown revision
+var ErrInvalidRevision = errors.New("Edit is based on unknown revision")
+
+// Edit replaces Delete characters at Position with Insert, positions are
+// counted in unicode code points
+type Edit struct {
+	Position int    `json:"position"`
+	Delete   int    `json:"delete"`
+	Insert   string `json:"insert"`
+}
+
+// maxHistory is number of latest edits kept for transforming late edits
+const maxHistory = 1000
+
+// Document is code shared by session participants, server holds authoritative
+// copy and every applied edit increases its revision
+type Document struct {
+	SessionID string `json:"sessionId"`
+	Lang      string `json:"lang"`
+	Text      string `json:"text"`
+	Revision  int    `json:"revision"`
+	// history holds latest edits, first of them was applied at revision base
+	history []*Edit
+	base    int
+}
+
+// NewDocument creates empty document of session
+func NewDocument(sessionID string) *Document {
+	return &Document{SessionID: sessionID}
+}
+
+// since returns edits applied after given revision
+func (d *Document) since(revision int) ([]*Edit, error) {
+	if revision < d.base || revision > d.Revision {
+		return nil, ErrInvalidRevision
+	}
+	return d.history[revision-d.base:], nil
+}
+
+// Restore prepares document loaded from storage for accepting edits, edits
+// made before it was stored can not be transformed anymore
+func (d *Document) Restore() {
+	d.history = nil
+	d.base = d.Revision
+}
+
+// Apply transforms edit made at given revision against edits applied since
+// then, applies it and returns transformed edit which clients should apply
+func (d *Document) Apply(revision int, edit *Edit) (*Edit, error) {
+	edits, err := d.since(revision)
+	if err != nil {
+		return nil, err
+	}
+	transformed := *edit
+	for _, applied := range edits {
+		transformed = transform(transformed, applied)
+	}
+	text := []rune(d.Text)
+	if transformed.Position < 0 || transformed.Delete < 0 || transformed.Position+transformed.Delete > len(text) {
+		return nil, ErrInvalidEdit
+	}
+	d.Text = splice(text, &transformed)
+	d.history = append(d.history, &transformed)
+	d.Revision++
+	if len(d.history) > maxHistory {
+		d.base += len(d.history) - maxHistory
+		d.history = d.history[len(d.history)-maxHistory:]
+	}
+	return &transformed, nil
+}
+
+// TransformPosition moves position made at given revision over edits applied since then
+func (d *Document) TransformPosition(revision, position int) int {
+	edits, err := d.since(revision)
+	if err != nil {
+		return position
+	}
+	for _, applied := range edits {
+		position = mapStart(position, applied)
+	}
+	return position
+}
+
+func splice(text []rune, edit *Edit) string {
+	result := make([]rune, 0, len(text)-edit.Delete+len(edit.Insert))
+	result = append(result, text[:edit.Position]...)
+	result = append(result, []rune(edit.Insert)...)
+	result = append(result, text[edit.Position+edit.Delete:]...)
+	return string(result)
+}
+
+// transform rewrites edit so it can be applied after applied edit, when both
+// insert at the same position applied edit goes first. Part of deleted range
+// which was already deleted by applied edit is dropped
+func transform(edit Edit, applied *Edit) Edit {
+	start := mapStart(edit.Position, applied)
+	end := mapEnd(edit.Position+edit.Delete, applied)
+	if end < start {
+		end = start
+	}
+	edit.Position = start
+	edit.Delete = end - start
+	return edit
+}
+
+// mapStart maps position at which edit starts, positions inside replaced
+// range move after applied insertion
+func mapStart(position int, applied *Edit) int {
+	switch {
+	case position < applied.Position:
+		return position
+	case position <= applied.Position+applied.Delete:
+		return applied.Position + len([]rune(applied.Insert))
+	}
+	return position - applied.Delete + len([]rune(applied.Insert))
+}
+
+// mapEnd maps position at which deleted range ends, positions inside replaced
+// range move before applied insertion
+func mapEnd(position int, applied *Edit) int {
+	switch {
+	case position <= applied.Position:
+		return position
+	case position <= applied.Position+applied.Delete:
+		return applied.Position
+	}
+	return position - applied.Delete + len([]rune(applied.Insert))
+}
@@ -0,0 +1,205 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+// concurrent is edit made at revision
+type concurrent struct {
+	revision int
+	edit     Edit
+}
+
+func TestDocumentApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		edits []concurrent
+		want  string
+		// transformed is edit clients apply for last edit
+		transformed Edit
+		wantErr     error
+	}{
+		{
+			name:        "insert",
+			text:        "ac",
+			edits:       []concurrent{{0, Edit{Position: 1, Insert: "b"}}},
+			want:        "abc",
+			transformed: Edit{Position: 1, Insert: "b"},
+		},
+		{
+			name:        "sequential edits",
+			text:        "abc",
+			edits:       []concurrent{{0, Edit{Position: 0, Delete: 1}}, {1, Edit{Position: 2, Insert: "d"}}},
+			want:        "bcd",
+			transformed: Edit{Position: 2, Insert: "d"},
+		},
+		{
+			name:        "concurrent insert before moves right",
+			text:        "abc",
+			edits:       []concurrent{{0, Edit{Position: 0, Insert: "xx"}}, {0, Edit{Position: 2, Insert: "y"}}},
+			want:        "xxabyc",
+			transformed: Edit{Position: 4, Insert: "y"},
+		},
+		{
+			name:        "concurrent insert at same position goes after",
+			text:        "abc",
+			edits:       []concurrent{{0, Edit{Position: 1, Insert: "x"}}, {0, Edit{Position: 1, Insert: "y"}}},
+			want:        "axybc",
+			transformed: Edit{Position: 2, Insert: "y"},
+		},
+		{
+			name:        "concurrent delete before moves left",
+			text:        "abcdef",
+			edits:       []concurrent{{0, Edit{Position: 0, Delete: 2}}, {0, Edit{Position: 4, Delete: 1}}},
+			want:        "cdf",
+			transformed: Edit{Position: 2, Delete: 1},
+		},
+		{
+			name:        "overlapping deletes drop deleted part",
+			text:        "abcdef",
+			edits:       []concurrent{{0, Edit{Position: 1, Delete: 3}}, {0, Edit{Position: 2, Delete: 3}}},
+			want:        "af",
+			transformed: Edit{Position: 1, Delete: 1},
+		},
+		{
+			name:        "delete of already deleted range",
+			text:        "abcdef",
+			edits:       []concurrent{{0, Edit{Position: 1, Delete: 4}}, {0, Edit{Position: 2, Delete: 2}}},
+			want:        "af",
+			transformed: Edit{Position: 1},
+		},
+		{
+			name:        "unicode positions",
+			text:        "čćž",
+			edits:       []concurrent{{0, Edit{Position: 1, Delete: 1, Insert: "đ"}}},
+			want:        "čđž",
+			transformed: Edit{Position: 1, Delete: 1, Insert: "đ"},
+		},
+		{
+			name:    "out of bounds",
+			text:    "abc",
+			edits:   []concurrent{{0, Edit{Position: 2, Delete: 2}}},
+			wantErr: ErrInvalidEdit,
+		},
+		{
+			name:    "negative position",
+			text:    "abc",
+			edits:   []concurrent{{0, Edit{Position: -1, Insert: "x"}}},
+			wantErr: ErrInvalidEdit,
+		},
+		{
+			name:    "future revision",
+			text:    "abc",
+			edits:   []concurrent{{1, Edit{Position: 0, Insert: "x"}}},
+			wantErr: ErrInvalidRevision,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &Document{Text: tt.text}
+			var transformed *Edit
+			var err error
+			for _, c := range tt.edits {
+				edit := c.edit
+				if transformed, err = doc.Apply(c.revision, &edit); err != nil {
+					break
+				}
+			}
+			if err != tt.wantErr {
+				t.Fatalf("Apply error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if doc.Text != tt.text || doc.Revision != len(tt.edits)-1 {
+					t.Errorf("failed edit changed document to %q at revision %v", doc.Text, doc.Revision)
+				}
+				return
+			}
+			if doc.Text != tt.want {
+				t.Errorf("Text = %q, want %q", doc.Text, tt.want)
+			}
+			if doc.Revision != len(tt.edits) {
+				t.Errorf("Revision = %v, want %v", doc.Revision, len(tt.edits))
+			}
+			if !reflect.DeepEqual(*transformed, tt.transformed) {
+				t.Errorf("transformed = %+v, want %+v", *transformed, tt.transformed)
+			}
+		})
+	}
+}
+
+func TestDocumentApplyConverges(t *testing.T) {
+	// clients which apply transformed edits in order server sent them end with server text
+	doc := &Document{Text: "hello world"}
+	client := []rune(doc.Text)
+	for _, c := range []concurrent{
+		{0, Edit{Position: 5, Delete: 6, Insert: "!"}},
+		{0, Edit{Position: 0, Delete: 1, Insert: "H"}},
+		{0, Edit{Position: 6, Delete: 5, Insert: "there"}},
+		{1, Edit{Position: 6, Insert: "?"}},
+	} {
+		edit := c.edit
+		transformed, err := doc.Apply(c.revision, &edit)
+		if err != nil {
+			t.Fatalf("Apply(%v, %+v) error: %v", c.revision, c.edit, err)
+		}
+		client = []rune(splice(client, transformed))
+	}
+	if string(client) != doc.Text {
+		t.Errorf("client text %q differs from server text %q", string(client), doc.Text)
+	}
+	if doc.Text != "Hello!there?" {
+		t.Errorf("Text = %q, want %q", doc.Text, "Hello!there?")
+	}
+}
+
+func TestDocumentHistory(t *testing.T) {
+	doc := &Document{}
+	for i := 0; i <= maxHistory; i++ {
+		if _, err := doc.Apply(i, &Edit{Position: i, Insert: "a"}); err != nil {
+			t.Fatalf("Apply at revision %v error: %v", i, err)
+		}
+	}
+	if _, err := doc.Apply(0, &Edit{Insert: "b"}); err != ErrInvalidRevision {
+		t.Errorf("edit older than history error = %v, want %v", err, ErrInvalidRevision)
+	}
+	if _, err := doc.Apply(1, &Edit{Insert: "b"}); err != nil {
+		t.Errorf("edit at oldest kept revision error: %v", err)
+	}
+
+	doc.Restore()
+	if _, err := doc.Apply(doc.Revision-1, &Edit{Insert: "c"}); err != ErrInvalidRevision {
+		t.Errorf("edit before restore error = %v, want %v", err, ErrInvalidRevision)
+	}
+}
+
+func TestDocumentTransformPosition(t *testing.T) {
+	doc := &Document{Text: "abcdef"}
+	for i, edit := range []Edit{{Position: 0, Insert: "xy"}, {Position: 4, Delete: 2}} {
+		if _, err := doc.Apply(i, &edit); err != nil {
+			t.Fatalf("Apply error: %v", err)
+		}
+	}
+	// text went from abcdef to xyabcdef to xyabef
+	tests := []struct {
+		revision int
+		position int
+		want     int
+	}{
+		{revision: 0, position: 0, want: 2},
+		{revision: 0, position: 1, want: 3},
+		{revision: 0, position: 3, want: 4},
+		{revision: 0, position: 6, want: 6},
+		{revision: 1, position: 1, want: 1},
+		{revision: 1, position: 5, want: 4},
+		{revision: 2, position: 3, want: 3},
+		// unknown revision leaves position as it is
+		{revision: 5, position: 3, want: 3},
+	}
+	for _, tt := range tests {
+		if got := doc.TransformPosition(tt.revision, tt.position); got != tt.want {
+			t.Errorf("TransformPosition(%v, %v) = %v, want %v", tt.revision, tt.position, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/metrics"
+	"go.uber.org/zap"
+)
+
+// Types of messages exchanged over collaboration channel
+const (
+	MessageSnapshot = "snapshot"
+	MessageEdit     = "edit"
+	MessageCursor   = "cursor"
+	MessageLanguage = "language"
+	MessageError    = "error"
+)
+
+// clientBuffer is number of messages client can fall behind before it is disconnected
+const clientBuffer = 256
+
+// saveDelay is how long changes of document are collected before they are persisted together
+const saveDelay = 500 * time.Millisecond
+
+// ErrUnknownMessage is returned when client sends message of unknown type
+var ErrUnknownMessage = errors.New("Unknown message type")
+
+// Cursor is cursor position with selection anchor, both are counted in unicode code points
+type Cursor struct {
+	Position int `json:"position"`
+	Anchor   int `json:"anchor"`
+}
+
+// Message is sent between clients and server over collaboration channel,
+// Revision is revision of document message is based on
+type Message struct {
+	Type     string  `json:"type"`
+	ClientID string  `json:"clientId,omitempty"`
+	Role     string  `json:"role,omitempty"`
+	Revision int     `json:"revision"`
+	Edit     *Edit   `json:"edit,omitempty"`
+	Cursor   *Cursor `json:"cursor,omitempty"`
+	Lang     string  `json:"lang,omitempty"`
+	Text     string  `json:"text,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// DocumentStore persists session documents and history of their changes
+type DocumentStore interface {
+	// LoadDocument returns stored document of session or new empty document
+	LoadDocument(sessionID string) (*Document, error)
+	// SaveChanges records events in history of document and saves document at once
+	SaveChanges(doc *Document, events []*Event) error
+}
+
+// Client is participant connected to session document, messages for it are
+// sent to Send. Done is closed when client leaves or is disconnected because
+// it can not keep up, connection of client has to be closed then
+type Client struct {
+	ID   string
+	Role string
+	Send chan *Message
+	Done chan struct{}
+	room *room
+}
+
+type room struct {
+	mu      sync.Mutex
+	doc     *Document
+	clients map[*Client]bool
+	// pending are changes which are not persisted yet, timer persists them
+	pending []*Event
+	timer   *time.Timer
+	// saveMu keeps changes persisted in order they were made
+	saveMu sync.Mutex
+}
+
+// drop removes client from room, it has to be called with room locked
+func (r *room) drop(client *Client) {
+	if r.clients[client] {
+		delete(r.clients, client)
+		close(client.Done)
+	}
+}
+
+// broadcast sends message to every client except skipped one, clients which
+// can not keep up are disconnected and have to join again
+func (r *room) broadcast(msg *Message, skip *Client) {
+	for client := range r.clients {
+		if client == skip {
+			continue
+		}
+		select {
+		case client.Send <- msg:
+		default:
+			r.drop(client)
+		}
+	}
+}
+
+// Hub holds authoritative documents of sessions which have connected clients
+type Hub struct {
+	mu    sync.Mutex
+	store DocumentStore
+	rooms map[string]*room
+}
+
+// NewHub creates hub which persists documents in store
+func NewHub(store DocumentStore) *Hub {
+	return &Hub{
+		store: store,
+		rooms: make(map[string]*room),
+	}
+}
+
+// Join connects participant with role to document of session, first message
+// client receives is document snapshot
+func (h *Hub) Join(sessionID, role string) (*Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.rooms[sessionID]
+	if !ok {
+		doc, err := h.store.LoadDocument(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		doc.Restore()
+		r = &room{doc: doc, clients: make(map[*Client]bool)}
+		h.rooms[sessionID] = r
+		metrics.ActiveSessions.Set(float64(len(h.rooms)))
+	}
+	client := &Client{
+		ID:   randomString(4),
+		Role: role,
+		Send: make(chan *Message, clientBuffer),
+		Done: make(chan struct{}),
+		room: r,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[client] = true
+	client.Send <- &Message{
+		Type:     MessageSnapshot,
+		ClientID: client.ID,
+		Role:     role,
+		Revision: r.doc.Revision,
+		Lang:     r.doc.Lang,
+		Text:     r.doc.Text,
+	}
+	return client, nil
+}
+
+// Leave disconnects client, document is persisted and dropped from memory when last client leaves
+func (h *Hub) Leave(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := client.room
+	r.mu.Lock()
+	r.drop(client)
+	empty := len(r.clients) == 0
+	r.mu.Unlock()
+	if empty && h.rooms[r.doc.SessionID] == r {
+		// hub stays locked so client joining again loads persisted document
+		h.flush(r)
+		delete(h.rooms, r.doc.SessionID)
+		metrics.ActiveSessions.Set(float64(len(h.rooms)))
+	}
+}
+
+// Flush persists pending changes of every document, it is called before server stops
+func (h *Hub) Flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, r := range h.rooms {
+		h.flush(r)
+	}
+}
+
+// Handle processes message received from client, edits are sent back to
+// their author too so it knows they were applied. Messages of client which
+// was disconnected are ignored
+func (h *Hub) Handle(client *Client, msg *Message) {
+	r := client.room
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.clients[client] {
+		return
+	}
+	if err := h.handle(r, client, msg); err != nil {
+		select {
+		case client.Send <- &Message{Type: MessageError, ClientID: client.ID, Error: err.Error()}:
+		default:
+		}
+	}
+}
+
+func (h *Hub) handle(r *room, client *Client, msg *Message) error {
+	switch msg.Type {
+	case MessageEdit:
+		if msg.Edit == nil {
+			return ErrInvalidEdit
+		}
+		edit, err := r.doc.Apply(msg.Revision, msg.Edit)
+		if err != nil {
+			return err
+		}
+		r.broadcast(&Message{
+			Type:     MessageEdit,
+			ClientID: client.ID,
+			Role:     client.Role,
+			Revision: r.doc.Revision,
+			Edit:     edit,
+		}, nil)
+		h.save(r, &Event{
+			Type:     MessageEdit,
+			ClientID: client.ID,
+			Role:     client.Role,
+			Revision: r.doc.Revision,
+			Edit:     edit,
+		})
+		return nil
+	case MessageCursor:
+		if msg.Cursor == nil {
+			return nil
+		}
+		r.broadcast(&Message{
+			Type:     MessageCursor,
+			ClientID: client.ID,
+			Role:     client.Role,
+			Revision: r.doc.Revision,
+			Cursor: &Cursor{
+				Position: r.doc.TransformPosition(msg.Revision, msg.Cursor.Position),
+				Anchor:   r.doc.TransformPosition(msg.Revision, msg.Cursor.Anchor),
+			},
+		}, client)
+		return nil
+	case MessageLanguage:
+		if _, ok := execution.MainNames[msg.Lang]; !ok {
+			return execution.ErrUnknownLanguage
+		}
+		r.doc.Lang = msg.Lang
+		r.broadcast(&Message{
+			Type:     MessageLanguage,
+			ClientID: client.ID,
+			Role:     client.Role,
+			Revision: r.doc.Revision,
+			Lang:     msg.Lang,
+		}, nil)
+		h.save(r, &Event{
+			Type:     MessageLanguage,
+			ClientID: client.ID,
+			Role:     client.Role,
+			Revision: r.doc.Revision,
+			Lang:     msg.Lang,
+		})
+		return nil
+	}
+	return ErrUnknownMessage
+}
+
+// save queues change of document, changes are persisted together once saveDelay
+// passes so participants do not wait for disk. It has to be called with room locked
+func (h *Hub) save(r *room, event *Event) {
+	event.Time = time.Now()
+	r.pending = append(r.pending, event)
+	if r.timer == nil {
+		r.timer = time.AfterFunc(saveDelay, func() { h.flush(r) })
+	}
+}
+
+// flush persists pending changes of room together with copy of its document
+func (h *Hub) flush(r *room) {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	r.mu.Lock()
+	events := r.pending
+	r.pending = nil
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	doc := *r.doc
+	doc.history = nil
+	r.mu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+	if err := h.store.SaveChanges(&doc, events); err != nil {
+		zap.L().Error("unable to save document",
+			zap.String("session", doc.SessionID),
+			zap.Int("events", len(events)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Document returns copy of current document of session
+func (h *Hub) Document(sessionID string) (*Document, error) {
+	h.mu.Lock()
+	r, ok := h.rooms[sessionID]
+	h.mu.Unlock()
+	if !ok {
+		return h.store.LoadDocument(sessionID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	doc := *r.doc
+	doc.history = nil
+	return &doc, nil
+}
@@ -0,0 +1,244 @@
+package session
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+)
+
+// memoryStore is DocumentStore which keeps documents in memory and reports
+// every save on saved
+type memoryStore struct {
+	mu    sync.Mutex
+	docs  map[string]Document
+	saved chan []*Event
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{docs: make(map[string]Document), saved: make(chan []*Event, 16)}
+}
+
+func (s *memoryStore) LoadDocument(sessionID string) (*Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.docs[sessionID]
+	if !ok {
+		return NewDocument(sessionID), nil
+	}
+	return &doc, nil
+}
+
+func (s *memoryStore) SaveChanges(doc *Document, events []*Event) error {
+	s.mu.Lock()
+	s.docs[doc.SessionID] = *doc
+	s.mu.Unlock()
+	s.saved <- events
+	return nil
+}
+
+// receive returns next message sent to client
+func receive(t *testing.T, client *Client) *Message {
+	t.Helper()
+	select {
+	case msg := <-client.Send:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("client %v received no message", client.ID)
+		return nil
+	}
+}
+
+// expectNone fails when message was sent to client
+func expectNone(t *testing.T, client *Client) {
+	t.Helper()
+	select {
+	case msg := <-client.Send:
+		t.Errorf("client %v received %+v, want no message", client.ID, msg)
+	default:
+	}
+}
+
+// join connects client and skips its snapshot
+func join(t *testing.T, hub *Hub, sessionID, role string) *Client {
+	t.Helper()
+	client, err := hub.Join(sessionID, role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := receive(t, client); msg.Type != MessageSnapshot {
+		t.Fatalf("first message = %+v, want snapshot", msg)
+	}
+	return client
+}
+
+func TestHubBroadcast(t *testing.T) {
+	hub := NewHub(newMemoryStore())
+	interviewer := join(t, hub, "s", RoleInterviewer)
+	candidate := join(t, hub, "s", RoleCandidate)
+
+	hub.Handle(candidate, &Message{Type: MessageEdit, Edit: &Edit{Insert: "abc"}})
+	for _, client := range []*Client{interviewer, candidate} {
+		msg := receive(t, client)
+		if msg.Type != MessageEdit || msg.ClientID != candidate.ID || msg.Role != RoleCandidate || msg.Revision != 1 || msg.Edit.Insert != "abc" {
+			t.Errorf("client %v received %+v, want edit of candidate at revision 1", client.ID, msg)
+		}
+	}
+
+	// cursor at revision 0 is moved past text inserted since then
+	hub.Handle(interviewer, &Message{Type: MessageCursor, Cursor: &Cursor{Position: 0, Anchor: 0}})
+	if msg := receive(t, candidate); msg.Type != MessageCursor || msg.Cursor.Position != 3 || msg.Cursor.Anchor != 3 {
+		t.Errorf("candidate received %+v, want cursor at 3", msg)
+	}
+	expectNone(t, interviewer)
+
+	hub.Handle(interviewer, &Message{Type: MessageLanguage, Revision: 1, Lang: execution.Java})
+	for _, client := range []*Client{interviewer, candidate} {
+		if msg := receive(t, client); msg.Type != MessageLanguage || msg.Lang != execution.Java {
+			t.Errorf("client %v received %+v, want java language", client.ID, msg)
+		}
+	}
+
+	tests := []struct {
+		name string
+		msg  *Message
+		want string
+	}{
+		{name: "unknown language", msg: &Message{Type: MessageLanguage, Lang: "cobol"}, want: execution.ErrUnknownLanguage.Error()},
+		{name: "edit without edit", msg: &Message{Type: MessageEdit}, want: ErrInvalidEdit.Error()},
+		{name: "unknown revision", msg: &Message{Type: MessageEdit, Revision: 5, Edit: &Edit{Insert: "x"}}, want: ErrInvalidRevision.Error()},
+		{name: "unknown type", msg: &Message{Type: "shout"}, want: ErrUnknownMessage.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub.Handle(candidate, tt.msg)
+			if msg := receive(t, candidate); msg.Type != MessageError || msg.Error != tt.want {
+				t.Errorf("candidate received %+v, want error %q", msg, tt.want)
+			}
+			expectNone(t, interviewer)
+		})
+	}
+
+	hub.Leave(interviewer)
+	hub.Handle(interviewer, &Message{Type: MessageEdit, Revision: 1, Edit: &Edit{Insert: "x"}})
+	expectNone(t, candidate)
+}
+
+func TestHubDisconnectsSlowClient(t *testing.T) {
+	hub := NewHub(newMemoryStore())
+	fast := join(t, hub, "s", RoleInterviewer)
+	slow := join(t, hub, "s", RoleCandidate)
+	for i := 0; i <= clientBuffer; i++ {
+		hub.Handle(fast, &Message{Type: MessageEdit, Revision: i, Edit: &Edit{Insert: "a"}})
+		<-fast.Send
+	}
+	select {
+	case <-slow.Done:
+	default:
+		t.Fatal("client which does not read messages is still connected")
+	}
+	select {
+	case <-fast.Done:
+		t.Fatal("client which reads messages was disconnected")
+	default:
+	}
+}
+
+func TestHubSaveBatching(t *testing.T) {
+	store := newMemoryStore()
+	hub := NewHub(store)
+	client := join(t, hub, "s", RoleCandidate)
+	for i, text := range []string{"a", "b", "c"} {
+		hub.Handle(client, &Message{Type: MessageEdit, Revision: i, Edit: &Edit{Position: i, Insert: text}})
+		receive(t, client)
+	}
+	hub.Handle(client, &Message{Type: MessageLanguage, Revision: 3, Lang: execution.Python})
+	receive(t, client)
+
+	select {
+	case events := <-store.saved:
+		if len(events) != 4 {
+			t.Fatalf("saved %v events together, want 4", len(events))
+		}
+		for i, event := range events {
+			if event.ClientID != client.ID || event.Time.IsZero() {
+				t.Errorf("event %v = %+v, want event of client with time", i, event)
+			}
+		}
+		if events[3].Type != MessageLanguage || events[3].Revision != 3 {
+			t.Errorf("last event = %+v, want language change at revision 3", events[3])
+		}
+	case <-time.After(saveDelay + time.Second):
+		t.Fatal("changes were not saved")
+	}
+	select {
+	case events := <-store.saved:
+		t.Fatalf("changes were saved again: %v events", len(events))
+	case <-time.After(saveDelay + 100*time.Millisecond):
+	}
+	if doc, _ := store.LoadDocument("s"); doc.Text != "abc" || doc.Revision != 3 || doc.Lang != execution.Python {
+		t.Errorf("saved document = %+v, want abc at revision 3 in python", doc)
+	}
+}
+
+func TestHubLeaveSaves(t *testing.T) {
+	store := newMemoryStore()
+	hub := NewHub(store)
+	first := join(t, hub, "s", RoleInterviewer)
+	second := join(t, hub, "s", RoleCandidate)
+	hub.Handle(first, &Message{Type: MessageEdit, Edit: &Edit{Insert: "abc"}})
+
+	hub.Leave(first)
+	select {
+	case events := <-store.saved:
+		t.Fatalf("changes were saved while client is connected: %v events", len(events))
+	default:
+	}
+	hub.Leave(second)
+	select {
+	case events := <-store.saved:
+		if len(events) != 1 {
+			t.Errorf("saved %v events, want 1", len(events))
+		}
+	default:
+		t.Fatal("changes were not saved once last client left")
+	}
+
+	client, err := hub.Join("s", RoleCandidate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := receive(t, client); msg.Text != "abc" || msg.Revision != 1 {
+		t.Errorf("snapshot = %+v, want saved document", msg)
+	}
+	// edits made before document was saved can not be transformed anymore
+	hub.Handle(client, &Message{Type: MessageEdit, Revision: 0, Edit: &Edit{Insert: "x"}})
+	if msg := receive(t, client); msg.Type != MessageError {
+		t.Errorf("edit at revision before save received %+v, want error", msg)
+	}
+}
+
+func TestHubFlush(t *testing.T) {
+	store := newMemoryStore()
+	hub := NewHub(store)
+	client := join(t, hub, "s", RoleCandidate)
+	hub.Handle(client, &Message{Type: MessageEdit, Edit: &Edit{Insert: "abc"}})
+
+	hub.Flush()
+	select {
+	case events := <-store.saved:
+		if len(events) != 1 {
+			t.Errorf("saved %v events, want 1", len(events))
+		}
+	default:
+		t.Fatal("changes were not saved on flush")
+	}
+	doc, err := hub.Document("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Text != "abc" {
+		t.Errorf("document text = %q, want abc", doc.Text)
+	}
+}
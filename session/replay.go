@@ -0,0 +1,49 @@
+package session
+
+import "time"
+
+// Event is recorded change of session document
+type Event struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	ClientID string    `json:"clientId"`
+	Role     string    `json:"role"`
+	// Revision is revision of document after change
+	Revision int    `json:"revision"`
+	Edit     *Edit  `json:"edit,omitempty"`
+	Lang     string `json:"lang,omitempty"`
+}
+
+// Replay reconstructs document of session by applying recorded events in
+// order while keep returns true for them
+func Replay(sessionID string, events []*Event, keep func(event *Event) bool) (*Document, error) {
+	doc := NewDocument(sessionID)
+	for _, event := range events {
+		if !keep(event) {
+			break
+		}
+		switch event.Type {
+		case MessageEdit:
+			if _, err := doc.Apply(doc.Revision, event.Edit); err != nil {
+				return nil, err
+			}
+		case MessageLanguage:
+			doc.Lang = event.Lang
+		}
+	}
+	return doc, nil
+}
+
+// ReplayAt reconstructs document of session as it was at given time
+func ReplayAt(sessionID string, events []*Event, at time.Time) (*Document, error) {
+	return Replay(sessionID, events, func(event *Event) bool {
+		return !event.Time.After(at)
+	})
+}
+
+// ReplayRevision reconstructs document of session at given revision
+func ReplayRevision(sessionID string, events []*Event, revision int) (*Document, error) {
+	return Replay(sessionID, events, func(event *Event) bool {
+		return event.Revision <= revision
+	})
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+// recorded returns events as hub records them: edits increase revision and
+// language changes keep it
+func recorded(start time.Time) []*Event {
+	return []*Event{
+		{Type: MessageEdit, Time: start, Revision: 1, Edit: &Edit{Position: 0, Insert: "def f():"}},
+		{Type: MessageLanguage, Time: start.Add(time.Second), Revision: 1, Lang: "python"},
+		{Type: MessageEdit, Time: start.Add(2 * time.Second), Revision: 2, Edit: &Edit{Position: 8, Insert: " pass"}},
+		{Type: MessageEdit, Time: start.Add(3 * time.Second), Revision: 3, Edit: &Edit{Position: 4, Delete: 1, Insert: "solve"}},
+		{Type: MessageLanguage, Time: start.Add(4 * time.Second), Revision: 3, Lang: "python3"},
+	}
+}
+
+func TestReplayAt(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		at       time.Time
+		text     string
+		lang     string
+		revision int
+	}{
+		{name: "before first event", at: start.Add(-time.Second), text: "", revision: 0},
+		{name: "at first event", at: start, text: "def f():", revision: 1},
+		{name: "between events", at: start.Add(1500 * time.Millisecond), text: "def f():", lang: "python", revision: 1},
+		{name: "after edit", at: start.Add(3 * time.Second), text: "def solve(): pass", lang: "python", revision: 3},
+		{name: "after every event", at: start.Add(time.Hour), text: "def solve(): pass", lang: "python3", revision: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ReplayAt("s", recorded(start), tt.at)
+			if err != nil {
+				t.Fatalf("ReplayAt error: %v", err)
+			}
+			if doc.SessionID != "s" || doc.Text != tt.text || doc.Lang != tt.lang || doc.Revision != tt.revision {
+				t.Errorf("ReplayAt = %+v, want text %q, lang %q at revision %v", doc, tt.text, tt.lang, tt.revision)
+			}
+		})
+	}
+}
+
+func TestReplayRevision(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		revision int
+		text     string
+		lang     string
+	}{
+		{revision: 0, text: ""},
+		{revision: 1, text: "def f():", lang: "python"},
+		{revision: 2, text: "def f(): pass", lang: "python"},
+		{revision: 3, text: "def solve(): pass", lang: "python3"},
+		{revision: 10, text: "def solve(): pass", lang: "python3"},
+	}
+	for _, tt := range tests {
+		doc, err := ReplayRevision("s", recorded(start), tt.revision)
+		if err != nil {
+			t.Fatalf("ReplayRevision(%v) error: %v", tt.revision, err)
+		}
+		if doc.Text != tt.text || doc.Lang != tt.lang {
+			t.Errorf("ReplayRevision(%v) = %q in %q, want %q in %q", tt.revision, doc.Text, doc.Lang, tt.text, tt.lang)
+		}
+	}
+}
+
+func TestReplayInvalidEdit(t *testing.T) {
+	events := []*Event{{Type: MessageEdit, Revision: 1, Edit: &Edit{Position: 3, Insert: "x"}}}
+	if _, err := Replay("s", events, func(*Event) bool { return true }); err != ErrInvalidEdit {
+		t.Errorf("Replay error = %v, want %v", err, ErrInvalidEdit)
+	}
+}
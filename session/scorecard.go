@@ -0,0 +1,83 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultRubric is used when rubric is not configured
+var DefaultRubric = Rubric{
+	Min:      1,
+	Max:      4,
+	Criteria: []string{"problem solving", "code quality", "communication"},
+}
+
+// Rubric describes criteria interviewer scores candidate on and range of scores
+type Rubric struct {
+	Min      int      `json:"min" mapstructure:"min"`
+	Max      int      `json:"max" mapstructure:"max"`
+	Criteria []string `json:"criteria" mapstructure:"criteria"`
+}
+
+// Note is private note interviewer takes during session
+type Note struct {
+	ID     uint64    `json:"id"`
+	Time   time.Time `json:"time"`
+	Author string    `json:"author"`
+	Text   string    `json:"text"`
+}
+
+// Scorecard is interviewer assessment of candidate by rubric criteria
+type Scorecard struct {
+	Scores    map[string]int `json:"scores"`
+	Summary   string         `json:"summary"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// Check checks that rubric scores are not negative, that min is not above max
+// and that criteria are named and unique
+func (r *Rubric) Check() error {
+	if r.Min < 0 {
+		return fmt.Errorf("min score %v can not be negative", r.Min)
+	}
+	if r.Min > r.Max {
+		return fmt.Errorf("min score %v is above max score %v", r.Min, r.Max)
+	}
+	if len(r.Criteria) == 0 {
+		return fmt.Errorf("rubric has no criteria")
+	}
+	seen := make(map[string]bool)
+	for _, c := range r.Criteria {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("criterion can not be empty")
+		}
+		if seen[c] {
+			return fmt.Errorf("criterion %q is repeated", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Validate checks that scorecard scores only rubric criteria within rubric range
+func (r *Rubric) Validate(card *Scorecard) error {
+	for criterion, score := range card.Scores {
+		if !r.has(criterion) {
+			return fmt.Errorf("unknown criterion %q", criterion)
+		}
+		if score < r.Min || score > r.Max {
+			return fmt.Errorf("score of %q has to be between %v and %v", criterion, r.Min, r.Max)
+		}
+	}
+	return nil
+}
+
+func (r *Rubric) has(criterion string) bool {
+	for _, c := range r.Criteria {
+		if c == criterion {
+			return true
+		}
+	}
+	return false
+}
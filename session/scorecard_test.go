@@ -0,0 +1,53 @@
+package session
+
+import "testing"
+
+func TestRubricValidate(t *testing.T) {
+	rubric := Rubric{Min: 1, Max: 4, Criteria: []string{"problem solving", "communication"}}
+	tests := []struct {
+		name    string
+		scores  map[string]int
+		wantErr bool
+	}{
+		{name: "no scores", scores: nil},
+		{name: "partial scores", scores: map[string]int{"communication": 3}},
+		{name: "bounds", scores: map[string]int{"problem solving": 1, "communication": 4}},
+		{name: "unknown criterion", scores: map[string]int{"speed": 2}, wantErr: true},
+		{name: "below min", scores: map[string]int{"communication": 0}, wantErr: true},
+		{name: "above max", scores: map[string]int{"problem solving": 5}, wantErr: true},
+		{name: "negative", scores: map[string]int{"problem solving": -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rubric.Validate(&Scorecard{Scores: tt.scores})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%v) error = %v, want error %v", tt.scores, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRubricCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		rubric  Rubric
+		wantErr bool
+	}{
+		{name: "default", rubric: DefaultRubric},
+		{name: "single score", rubric: Rubric{Min: 0, Max: 0, Criteria: []string{"communication"}}},
+		{name: "min above max", rubric: Rubric{Min: 4, Max: 1, Criteria: []string{"communication"}}, wantErr: true},
+		{name: "negative min", rubric: Rubric{Min: -1, Max: 4, Criteria: []string{"communication"}}, wantErr: true},
+		{name: "negative max", rubric: Rubric{Min: -4, Max: -1, Criteria: []string{"communication"}}, wantErr: true},
+		{name: "no criteria", rubric: Rubric{Min: 1, Max: 4}, wantErr: true},
+		{name: "empty criterion", rubric: Rubric{Min: 1, Max: 4, Criteria: []string{"communication", " "}}, wantErr: true},
+		{name: "repeated criterion", rubric: Rubric{Min: 1, Max: 4, Criteria: []string{"communication", "communication"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rubric.Check()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Roles of session participants
+const (
+	RoleInterviewer = "interviewer"
+	RoleCandidate   = "candidate"
+)
+
+// States of session
+const (
+	StatePending = "pending"
+	StateActive  = "active"
+	StateEnded   = "ended"
+)
+
+// Errors of session state transitions and access
+var (
+	ErrInvalidToken    = errors.New("Invalid session token")
+	ErrForbidden       = errors.New("Action is not allowed for this role")
+	ErrNotActive       = errors.New("Session is not active")
+	ErrAlreadyStarted  = errors.New("Session has already started")
+	ErrProblemNotInSet = errors.New("Problem is not assigned to session")
+	ErrNegativeLimit   = errors.New("Session time limit can not be negative")
+)
+
+// Session is single interview with its participants and assigned problems
+type Session struct {
+	ID               string   `json:"id"`
+	Title            string   `json:"title"`
+	ProblemIDs       []string `json:"problemIds"`
+	Interviewer      string   `json:"interviewer"`
+	Candidate        string   `json:"candidate"`
+	InterviewerToken string   `json:"interviewerToken,omitempty"`
+	CandidateToken   string   `json:"candidateToken,omitempty"`
+	// TimeLimit is in minutes, zero means session lasts until it is ended
+	TimeLimit int        `json:"timeLimit"`
+	CreatedAt time.Time  `json:"createdAt"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+// New creates pending session with generated id and join tokens, time limit is in minutes
+func New(title, interviewer, candidate string, problemIDs []string, timeLimit int) (*Session, error) {
+	if timeLimit < 0 {
+		return nil, ErrNegativeLimit
+	}
+	return &Session{
+		ID:               randomString(8),
+		Title:            title,
+		ProblemIDs:       problemIDs,
+		Interviewer:      interviewer,
+		Candidate:        candidate,
+		InterviewerToken: randomString(16),
+		CandidateToken:   randomString(16),
+		TimeLimit:        timeLimit,
+		CreatedAt:        time.Now(),
+	}, nil
+}
+
+func randomString(size int) string {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Deadline returns time when session expires, zero time if session has no deadline
+func (s *Session) Deadline() time.Time {
+	if s.StartedAt == nil || s.TimeLimit == 0 {
+		return time.Time{}
+	}
+	return s.StartedAt.Add(time.Duration(s.TimeLimit) * time.Minute)
+}
+
+// State returns state of session at given time, session past its deadline is ended
+func (s *Session) State(now time.Time) string {
+	switch {
+	case s.StartedAt == nil:
+		return StatePending
+	case s.EndedAt != nil:
+		return StateEnded
+	case !s.Deadline().IsZero() && now.After(s.Deadline()):
+		return StateEnded
+	}
+	return StateActive
+}
+
+// Role returns role of participant with given token
+func (s *Session) Role(token string) (string, error) {
+	switch {
+	case token == "":
+		return "", ErrInvalidToken
+	// tokens are compared in constant time so they can not be guessed by timing
+	case subtle.ConstantTimeCompare([]byte(token), []byte(s.InterviewerToken)) == 1:
+		return RoleInterviewer, nil
+	case subtle.ConstantTimeCompare([]byte(token), []byte(s.CandidateToken)) == 1:
+		return RoleCandidate, nil
+	}
+	return "", ErrInvalidToken
+}
+
+// ForRole returns copy of session with only data that participant with role
+// can see, join tokens are visible to interviewer only
+func (s *Session) ForRole(role string) *Session {
+	result := *s
+	if role != RoleInterviewer {
+		result.InterviewerToken = ""
+		result.CandidateToken = ""
+	}
+	return &result
+}
+
+// HasProblem checks if problem is assigned to session
+func (s *Session) HasProblem(problemID string) bool {
+	for _, id := range s.ProblemIDs {
+		if id == problemID {
+			return true
+		}
+	}
+	return false
+}
+
+// Start starts pending session
+func (s *Session) Start(now time.Time) error {
+	if s.StartedAt != nil {
+		return ErrAlreadyStarted
+	}
+	s.StartedAt = &now
+	return nil
+}
+
+// End ends active session, if session expired its end is its deadline
+func (s *Session) End(now time.Time) error {
+	if s.State(now) == StatePending {
+		return ErrNotActive
+	}
+	if s.EndedAt != nil {
+		return nil
+	}
+	if deadline := s.Deadline(); !deadline.IsZero() && now.After(deadline) {
+		now = deadline
+	}
+	s.EndedAt = &now
+	return nil
+}
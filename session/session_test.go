@@ -0,0 +1,140 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func newSession(t *testing.T, timeLimit int) *Session {
+	s, err := New("Screen", "ivan", "ana", []string{"two-sum"}, timeLimit)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return s
+}
+
+func TestNew(t *testing.T) {
+	s := newSession(t, 45)
+	if s.ID == "" || s.InterviewerToken == "" || s.CandidateToken == "" || s.InterviewerToken == s.CandidateToken {
+		t.Errorf("New = %+v, want id and distinct tokens", s)
+	}
+	if other := newSession(t, 45); other.ID == s.ID || other.CandidateToken == s.CandidateToken {
+		t.Error("sessions share id or token")
+	}
+	if _, err := New("Screen", "ivan", "ana", nil, -1); err != ErrNegativeLimit {
+		t.Errorf("New with negative time limit error = %v, want %v", err, ErrNegativeLimit)
+	}
+}
+
+func TestRole(t *testing.T) {
+	s := newSession(t, 0)
+	other := newSession(t, 0)
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{name: "interviewer", token: s.InterviewerToken, want: RoleInterviewer},
+		{name: "candidate", token: s.CandidateToken, want: RoleCandidate},
+		{name: "empty", token: "", wantErr: ErrInvalidToken},
+		{name: "token of other session", token: other.InterviewerToken, wantErr: ErrInvalidToken},
+		{name: "prefix of token", token: s.CandidateToken[:8], wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := s.Role(tt.token)
+			if role != tt.want || err != tt.wantErr {
+				t.Errorf("Role = %q, %v, want %q, %v", role, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForRole(t *testing.T) {
+	s := newSession(t, 0)
+	if got := s.ForRole(RoleInterviewer); got.CandidateToken != s.CandidateToken || got.InterviewerToken != s.InterviewerToken {
+		t.Error("interviewer does not see join tokens")
+	}
+	for _, role := range []string{RoleCandidate, ""} {
+		if got := s.ForRole(role); got.CandidateToken != "" || got.InterviewerToken != "" {
+			t.Errorf("role %q sees join tokens", role)
+		}
+	}
+	if s.CandidateToken == "" {
+		t.Error("ForRole changed session")
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		timeLimit int
+		// end is how long after start session is ended, zero means it is not ended
+		end time.Duration
+		at  time.Duration
+		// want is state at given time after start
+		want    string
+		endedAt time.Duration
+	}{
+		{name: "active without limit", at: 10 * time.Hour, want: StateActive},
+		{name: "active before deadline", timeLimit: 30, at: 29 * time.Minute, want: StateActive},
+		{name: "expired after deadline", timeLimit: 30, at: 31 * time.Minute, want: StateEnded},
+		{name: "ended", end: 5 * time.Minute, at: 6 * time.Minute, want: StateEnded, endedAt: 5 * time.Minute},
+		{name: "ended after deadline ends at deadline", timeLimit: 30, end: time.Hour, at: time.Hour, want: StateEnded, endedAt: 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSession(t, tt.timeLimit)
+			if state := s.State(start); state != StatePending {
+				t.Fatalf("state before start = %v, want %v", state, StatePending)
+			}
+			if err := s.End(start); err != ErrNotActive {
+				t.Fatalf("End of pending session error = %v, want %v", err, ErrNotActive)
+			}
+			if err := s.Start(start); err != nil {
+				t.Fatalf("Start error: %v", err)
+			}
+			if err := s.Start(start); err != ErrAlreadyStarted {
+				t.Fatalf("second Start error = %v, want %v", err, ErrAlreadyStarted)
+			}
+			if tt.end != 0 {
+				if err := s.End(start.Add(tt.end)); err != nil {
+					t.Fatalf("End error: %v", err)
+				}
+				// ending again keeps first end
+				if err := s.End(start.Add(tt.end + time.Hour)); err != nil {
+					t.Fatalf("second End error: %v", err)
+				}
+				if !s.EndedAt.Equal(start.Add(tt.endedAt)) {
+					t.Errorf("EndedAt = %v, want %v", s.EndedAt, start.Add(tt.endedAt))
+				}
+			}
+			if state := s.State(start.Add(tt.at)); state != tt.want {
+				t.Errorf("State = %v, want %v", state, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	s := newSession(t, 30)
+	if !s.Deadline().IsZero() {
+		t.Error("pending session has deadline")
+	}
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := s.Start(start); err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(30 * time.Minute); !s.Deadline().Equal(want) {
+		t.Errorf("Deadline = %v, want %v", s.Deadline(), want)
+	}
+	unlimited := newSession(t, 0)
+	if err := unlimited.Start(start); err != nil {
+		t.Fatal(err)
+	}
+	if !unlimited.Deadline().IsZero() {
+		t.Error("session without time limit has deadline")
+	}
+}
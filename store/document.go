@@ -0,0 +1,52 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/Strovala/crackview/session"
+	bolt "go.etcd.io/bbolt"
+)
+
+// LoadDocument returns stored document of session or new empty document
+func (s *Store) LoadDocument(sessionID string) (*session.Document, error) {
+	doc := &session.Document{}
+	err := s.getKey(documentsBucket, []byte(sessionID), doc)
+	if err == ErrNotFound {
+		return session.NewDocument(sessionID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// SaveChanges appends events to history of session document and saves
+// document in single transaction
+func (s *Store) SaveChanges(doc *session.Document, events []*session.Event) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, event := range events {
+			if err := appendTx(tx, eventsBucket, doc.SessionID, func(uint64) {}, event); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(documentsBucket).Put([]byte(doc.SessionID), data)
+	})
+}
+
+// Events returns history of session document in order events were recorded
+func (s *Store) Events(sessionID string) ([]*session.Event, error) {
+	var result []*session.Event
+	err := s.list(eventsBucket, sessionID, func(data []byte) error {
+		event := &session.Event{}
+		if err := json.Unmarshal(data, event); err != nil {
+			return err
+		}
+		result = append(result, event)
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Strovala/crackview/execution"
+	"github.com/Strovala/crackview/problem"
+)
+
+// Kinds of executions
+const (
+	KindExecute = "execute"
+	KindRun     = "run"
+	KindSubmit  = "submit"
+)
+
+// Execution is persisted code run
+type Execution struct {
+	ID          uint64 `json:"id"`
+	Kind        string `json:"kind"`
+	SessionID   string `json:"sessionId"`
+	CandidateID string `json:"candidateId"`
+	ProblemID   string `json:"problemId"`
+	Lang        string `json:"lang"`
+	Code        string `json:"code"`
+	Input       string `json:"input"`
+	Verdict     string `json:"verdict"`
+	// Result is set for plain code runs and Submission for runs against problem tests
+	Result     *execution.CodeResult     `json:"result,omitempty"`
+	Submission *problem.SubmissionResult `json:"submission,omitempty"`
+	CreatedAt  time.Time                 `json:"createdAt"`
+	// Duration is in milliseconds
+	Duration int64 `json:"duration"`
+}
+
+// ExecutionFilter selects executions, empty fields match everything
+type ExecutionFilter struct {
+	Kind        string
+	SessionID   string
+	CandidateID string
+	ProblemID   string
+	Lang        string
+	Verdict     string
+	Since       time.Time
+	Until       time.Time
+	// Limit is maximum number of returned executions, newest ones are kept,
+	// zero means no limit
+	Limit int
+}
+
+// Match checks if execution is selected by filter
+func (f *ExecutionFilter) Match(e *Execution) bool {
+	switch {
+	case f.Kind != "" && f.Kind != e.Kind:
+		return false
+	case f.SessionID != "" && f.SessionID != e.SessionID:
+		return false
+	case f.CandidateID != "" && f.CandidateID != e.CandidateID:
+		return false
+	case f.ProblemID != "" && f.ProblemID != e.ProblemID:
+		return false
+	case f.Lang != "" && f.Lang != e.Lang:
+		return false
+	case f.Verdict != "" && f.Verdict != e.Verdict:
+		return false
+	case !f.Since.IsZero() && e.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && e.CreatedAt.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// SaveExecution persists execution and sets its id
+func (s *Store) SaveExecution(e *Execution) error {
+	return s.put(executionsBucket, func(id uint64) { e.ID = id }, e)
+}
+
+// Execution returns execution with given id
+func (s *Store) Execution(id uint64) (*Execution, error) {
+	result := &Execution{}
+	if err := s.get(executionsBucket, id, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Executions returns executions selected by filter in order they were created,
+// executions are scanned from newest so limit keeps newest ones
+func (s *Store) Executions(filter ExecutionFilter) ([]*Execution, error) {
+	var result []*Execution
+	err := s.eachNewest(executionsBucket, func(data []byte) (bool, error) {
+		e := &Execution{}
+		if err := json.Unmarshal(data, e); err != nil {
+			return false, err
+		}
+		if filter.Match(e) {
+			result = append(result, e)
+		}
+		return filter.Limit == 0 || len(result) < filter.Limit, nil
+	})
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, err
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// openTestStore opens store in temporary directory which is removed with test
+func openTestStore(t *testing.T) *Store {
+	dir, err := ioutil.TempDir("", "crackview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(filepath.Join(dir, "crackview.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(dir)
+	})
+	return s
+}
+
+func TestExecutions(t *testing.T) {
+	s := openTestStore(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	saved := []*Execution{
+		{Kind: KindExecute, SessionID: "s1", Lang: "python", Verdict: "OK"},
+		{Kind: KindSubmit, SessionID: "s1", ProblemID: "two-sum", Lang: "java", Verdict: "Accepted"},
+		{Kind: KindSubmit, SessionID: "s2", ProblemID: "two-sum", Lang: "python", Verdict: "WrongAnswer"},
+		{Kind: KindRun, SessionID: "s2", ProblemID: "lru", Lang: "cpp", Verdict: "Accepted"},
+		{Kind: KindSubmit, SessionID: "s1", ProblemID: "lru", Lang: "python", Verdict: "Accepted"},
+	}
+	for i, e := range saved {
+		e.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		if err := s.SaveExecution(e); err != nil {
+			t.Fatalf("SaveExecution error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter ExecutionFilter
+		// want are indices of saved executions
+		want []int
+	}{
+		{name: "all", want: []int{0, 1, 2, 3, 4}},
+		{name: "session", filter: ExecutionFilter{SessionID: "s1"}, want: []int{0, 1, 4}},
+		{name: "problem", filter: ExecutionFilter{ProblemID: "two-sum"}, want: []int{1, 2}},
+		{name: "verdict", filter: ExecutionFilter{Verdict: "Accepted"}, want: []int{1, 3, 4}},
+		{name: "kind and lang", filter: ExecutionFilter{Kind: KindSubmit, Lang: "python"}, want: []int{2, 4}},
+		{name: "time range", filter: ExecutionFilter{Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)}, want: []int{1, 2, 3}},
+		{name: "limit keeps newest", filter: ExecutionFilter{Limit: 2}, want: []int{3, 4}},
+		{name: "limit of filtered", filter: ExecutionFilter{SessionID: "s1", Limit: 2}, want: []int{1, 4}},
+		{name: "limit above count", filter: ExecutionFilter{Verdict: "Accepted", Limit: 10}, want: []int{1, 3, 4}},
+		{name: "no match", filter: ExecutionFilter{SessionID: "s3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Executions(tt.filter)
+			if err != nil {
+				t.Fatalf("Executions error: %v", err)
+			}
+			var ids []uint64
+			for _, e := range got {
+				ids = append(ids, e.ID)
+			}
+			var want []uint64
+			for _, i := range tt.want {
+				want = append(want, saved[i].ID)
+			}
+			if !reflect.DeepEqual(ids, want) {
+				t.Errorf("Executions ids = %v, want %v", ids, want)
+			}
+		})
+	}
+}
+
+func TestExecution(t *testing.T) {
+	s := openTestStore(t)
+	e := &Execution{Kind: KindExecute, Code: "print(1)", CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := s.SaveExecution(e); err != nil {
+		t.Fatalf("SaveExecution error: %v", err)
+	}
+	got, err := s.Execution(e.ID)
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("Execution = %+v, want %+v", got, e)
+	}
+	if _, err := s.Execution(e.ID + 1); err != ErrNotFound {
+		t.Errorf("Execution of missing id error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/Strovala/crackview/session"
+)
+
+// AddNote appends interviewer note to session and sets its id
+func (s *Store) AddNote(sessionID string, note *session.Note) error {
+	return s.append(notesBucket, sessionID, func(id uint64) { note.ID = id }, note)
+}
+
+// Notes returns notes of session in order they were taken
+func (s *Store) Notes(sessionID string) ([]*session.Note, error) {
+	var result []*session.Note
+	err := s.list(notesBucket, sessionID, func(data []byte) error {
+		note := &session.Note{}
+		if err := json.Unmarshal(data, note); err != nil {
+			return err
+		}
+		result = append(result, note)
+		return nil
+	})
+	return result, err
+}
+
+// SaveScorecard creates or updates scorecard of session
+func (s *Store) SaveScorecard(sessionID string, card *session.Scorecard) error {
+	return s.putKey(scorecardsBucket, []byte(sessionID), card)
+}
+
+// Scorecard returns scorecard of session or empty scorecard if there is none
+func (s *Store) Scorecard(sessionID string) (*session.Scorecard, error) {
+	card := &session.Scorecard{}
+	err := s.getKey(scorecardsBucket, []byte(sessionID), card)
+	if err == ErrNotFound {
+		return &session.Scorecard{Scores: make(map[string]int)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
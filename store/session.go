@@ -0,0 +1,47 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/Strovala/crackview/session"
+)
+
+// SaveSession creates or updates session
+func (s *Store) SaveSession(sess *session.Session) error {
+	return s.putKey(sessionsBucket, []byte(sess.ID), sess)
+}
+
+// UpdateSession applies update to stored session atomically and returns updated session
+func (s *Store) UpdateSession(id string, update func(sess *session.Session) error) (*session.Session, error) {
+	result := &session.Session{}
+	err := s.updateKey(sessionsBucket, []byte(id), result, func() error {
+		return update(result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Session returns session with given id
+func (s *Store) Session(id string) (*session.Session, error) {
+	result := &session.Session{}
+	if err := s.getKey(sessionsBucket, []byte(id), result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Sessions returns all sessions
+func (s *Store) Sessions() ([]*session.Session, error) {
+	var result []*session.Session
+	err := s.each(sessionsBucket, func(data []byte) (bool, error) {
+		sess := &session.Session{}
+		if err := json.Unmarshal(data, sess); err != nil {
+			return false, err
+		}
+		result = append(result, sess)
+		return true, nil
+	})
+	return result, err
+}
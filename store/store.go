@@ -0,0 +1,203 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when requested record does not exist
+var ErrNotFound = errors.New("Not found")
+
+var (
+	executionsBucket = []byte("executions")
+	sessionsBucket   = []byte("sessions")
+	documentsBucket  = []byte("documents")
+	scorecardsBucket = []byte("scorecards")
+	// eventsBucket and notesBucket hold bucket for every session
+	eventsBucket = []byte("events")
+	notesBucket  = []byte("notes")
+)
+
+var buckets = [][]byte{executionsBucket, sessionsBucket, documentsBucket, scorecardsBucket, eventsBucket, notesBucket}
+
+// Store persists data in embedded database file
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens store in file at path, file is created if it does not exist
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// key encodes id so that keys are sorted in order records were created
+func key(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+// put stores value under next id of bucket, setID is called with new id before value is serialized
+func (s *Store) put(bucket []byte, setID func(id uint64), value interface{}) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		setID(id)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return b.Put(key(id), data)
+	})
+}
+
+// putKey stores value under given key of bucket, replacing existing value
+func (s *Store) putKey(bucket []byte, k []byte, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(k, data)
+	})
+}
+
+// updateKey deserializes value stored under key into dest, calls update and
+// stores dest back in single transaction, nothing is stored if update fails
+func (s *Store) updateKey(bucket []byte, k []byte, dest interface{}, update func() error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		data := b.Get(k)
+		if data == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(data, dest); err != nil {
+			return err
+		}
+		if err := update(); err != nil {
+			return err
+		}
+		data, err := json.Marshal(dest)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, data)
+	})
+}
+
+// append stores value under next id of sub bucket of bucket, setID is called
+// with new id before value is serialized
+func (s *Store) append(bucket []byte, sub string, setID func(id uint64), value interface{}) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return appendTx(tx, bucket, sub, setID, value)
+	})
+}
+
+// appendTx is append within transaction tx
+func appendTx(tx *bolt.Tx, bucket []byte, sub string, setID func(id uint64), value interface{}) error {
+	b, err := tx.Bucket(bucket).CreateBucketIfNotExists([]byte(sub))
+	if err != nil {
+		return err
+	}
+	id, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	setID(id)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return b.Put(key(id), data)
+}
+
+// list calls fn with every value in sub bucket of bucket in order they were appended
+func (s *Store) list(bucket []byte, sub string, fn func(data []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket).Bucket([]byte(sub))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			return fn(v)
+		})
+	})
+}
+
+// get deserializes value stored under id in bucket into dest
+func (s *Store) get(bucket []byte, id uint64, dest interface{}) error {
+	return s.getKey(bucket, key(id), dest)
+}
+
+// getKey deserializes value stored under key in bucket into dest
+func (s *Store) getKey(bucket []byte, k []byte, dest interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(k)
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, dest)
+	})
+}
+
+// each calls fn with every value in bucket in order they were created until fn returns false
+func (s *Store) each(bucket []byte, fn func(data []byte) (bool, error)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			next, err := fn(v)
+			if err != nil {
+				return err
+			}
+			if !next {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// eachNewest calls fn with every value in bucket from newest to oldest until fn returns false
+func (s *Store) eachNewest(bucket []byte, fn func(data []byte) (bool, error)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			next, err := fn(v)
+			if err != nil {
+				return err
+			}
+			if !next {
+				return nil
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,249 @@
+package stress
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Defaults used when argument spec does not declare them
+const (
+	defaultMinSize   = 0
+	defaultMaxSize   = 10
+	defaultMinLength = 1
+	defaultMaxLength = 8
+	defaultMin       = -100
+	defaultMax       = 100
+	defaultAlphabet  = "abcdefghijklmnopqrstuvwxyz"
+)
+
+// maxKeyTries limits how many times unique key of set or map is generated before giving up
+const maxKeyTries = 100
+
+// ErrUnsupportedType is returned when value of type can not be generated
+var ErrUnsupportedType = errors.New("Unsupported type for random generator")
+
+// Arg declares how random values of argument are generated. Size is [min, max]
+// number of elements of arrays, sets and maps, Length is [min, max] length of
+// strings, Range is [min, max] of numbers and Alphabet is characters strings
+// are made of. Scaled marks int argument which is size of input, such as n of
+// fib(n), InputOfSize sets it to size
+type Arg struct {
+	Size     []int     `yaml:"size" json:"size,omitempty"`
+	Length   []int     `yaml:"length" json:"length,omitempty"`
+	Range    []float64 `yaml:"range" json:"range,omitempty"`
+	Alphabet string    `yaml:"alphabet" json:"alphabet,omitempty"`
+	Scaled   bool      `yaml:"scaled" json:"scaled,omitempty"`
+}
+
+// Generator generates random inputs for solution with given argument types
+type Generator struct {
+	types []string
+	args  []Arg
+	rand  *rand.Rand
+}
+
+// New creates generator for argument types in parser syntax, args may declare
+// fewer arguments than there are types, rest use defaults
+func New(types []string, args []Arg, seed int64) (*Generator, error) {
+	g := &Generator{types: types, args: make([]Arg, len(types)), rand: rand.New(rand.NewSource(seed))}
+	copy(g.args, args)
+	for i, t := range types {
+		if _, _, err := split(t); err != nil {
+			return nil, err
+		}
+		if err := g.args[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (a Arg) validate() error {
+	if (len(a.Size) != 0 && len(a.Size) != 2) || (len(a.Length) != 0 && len(a.Length) != 2) || (len(a.Range) != 0 && len(a.Range) != 2) {
+		return errors.New("size, length and range have to be [min, max]")
+	}
+	if (len(a.Size) == 2 && (a.Size[0] < 0 || a.Size[0] > a.Size[1])) || (len(a.Length) == 2 && (a.Length[0] < 0 || a.Length[0] > a.Length[1])) {
+		return errors.New("size and length have to be non negative with min not greater than max")
+	}
+	if len(a.Range) == 2 && !(a.Range[0] <= a.Range[1]) {
+		return errors.New("range min can not be greater than max")
+	}
+	return nil
+}
+
+// Input generates input in parser syntax, scale between 0 and 1 limits sizes
+// and lengths to that part of their range so small inputs can be generated first
+func (g *Generator) Input(scale float64) string {
+	lines := make([]string, len(g.types))
+	for i, t := range g.types {
+		lines[i] = g.value(t, g.args[i], scale, -1) + " => " + t
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InputOfSize generates input in parser syntax where every array, set and map
+// has n elements, every string argument has length n and every scaled int
+// argument is n, elements are generated as by Input with full scale
+func (g *Generator) InputOfSize(n int) string {
+	lines := make([]string, len(g.types))
+	for i, t := range g.types {
+		lines[i] = g.value(t, g.args[i], 1, n) + " => " + t
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Scalable reports whether any argument grows with size passed to InputOfSize
+func (g *Generator) Scalable() bool {
+	for i, t := range g.types {
+		container, kinds, _ := split(t)
+		if container != "" || kinds[0] == "string" || (kinds[0] == "int" && g.args[i].Scaled) {
+			return true
+		}
+	}
+	return false
+}
+
+// value generates value of type, size of container, length of string argument
+// and value of scaled int argument are random when n is negative
+func (g *Generator) value(valType string, arg Arg, scale float64, n int) string {
+	container, kinds, _ := split(valType)
+	if container == "" {
+		if n >= 0 && kinds[0] == "int" && arg.Scaled {
+			return strconv.Itoa(n)
+		}
+		if n >= 0 && kinds[0] == "string" {
+			arg.Length = []int{n, n}
+		}
+		return g.simple(kinds[0], arg, scale)
+	}
+	size := n
+	if size < 0 {
+		size = g.between(bounds(arg.Size, defaultMinSize, defaultMaxSize), scale)
+	}
+	var items []string
+	seen := map[string]bool{}
+	for tries := 0; len(items) < size && tries < size*maxKeyTries; tries++ {
+		item := g.simple(kinds[0], arg, scale)
+		if container != "[]" {
+			// sets and map keys have to be unique
+			if seen[item] {
+				continue
+			}
+			seen[item] = true
+		}
+		if container == "{}" {
+			item += ": " + g.simple(kinds[1], arg, scale)
+		}
+		items = append(items, item)
+	}
+	open, close := container[:1], container[1:]
+	return open + strings.Join(items, ", ") + close
+}
+
+func (g *Generator) simple(kind string, arg Arg, scale float64) string {
+	switch kind {
+	case "int":
+		r := floatBounds(arg.Range)
+		return strconv.FormatInt(g.intBetween(toInt(r[0]), toInt(r[1])), 10)
+	case "float":
+		r := floatBounds(arg.Range)
+		value := r[0] + g.rand.Float64()*(r[1]-r[0])
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	case "bool":
+		return strconv.FormatBool(g.rand.Intn(2) == 1)
+	}
+	alphabet := []rune(arg.Alphabet)
+	if len(alphabet) == 0 {
+		alphabet = []rune(defaultAlphabet)
+	}
+	length := g.between(bounds(arg.Length, defaultMinLength, defaultMaxLength), scale)
+	runes := make([]rune, length)
+	for i := range runes {
+		runes[i] = alphabet[g.rand.Intn(len(alphabet))]
+	}
+	return strconv.Quote(string(runes))
+}
+
+// between returns random number between min and part of range given by scale
+func (g *Generator) between(b [2]int, scale float64) int {
+	max := b[0] + int(scale*float64(b[1]-b[0])+0.5)
+	if max < b[0] {
+		max = b[0]
+	}
+	return int(g.intBetween(int64(b[0]), int64(max)))
+}
+
+// intBetween returns random number in [min, max], span is computed in uint64
+// so ranges wider than int64 can hold do not overflow
+func (g *Generator) intBetween(min, max int64) int64 {
+	span := uint64(max) - uint64(min) + 1
+	if span == 0 {
+		// range covers every int64
+		return int64(g.rand.Uint64())
+	}
+	if span <= math.MaxInt64 {
+		return min + g.rand.Int63n(int64(span))
+	}
+	for {
+		if v := g.rand.Uint64(); v < span {
+			return int64(uint64(min) + v)
+		}
+	}
+}
+
+// toInt converts float bound of range to int64, values outside of int64 are clamped
+func toInt(value float64) int64 {
+	switch {
+	case value >= math.MaxInt64:
+		return math.MaxInt64
+	case value <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(value)
+}
+
+func bounds(values []int, min, max int) [2]int {
+	if len(values) == 2 && values[0] <= values[1] {
+		return [2]int{values[0], values[1]}
+	}
+	return [2]int{min, max}
+}
+
+func floatBounds(values []float64) [2]float64 {
+	if len(values) == 2 && values[0] <= values[1] {
+		return [2]float64{values[0], values[1]}
+	}
+	return [2]float64{defaultMin, defaultMax}
+}
+
+// split splits type in parser syntax to container and kinds of its elements
+func split(valType string) (string, []string, error) {
+	valType = strings.TrimSpace(valType)
+	container := ""
+	if len(valType) >= 2 {
+		switch valType[:2] {
+		case "[]", "()", "{}":
+			container = valType[:2]
+			valType = valType[2:]
+		}
+	}
+	kinds := []string{valType}
+	if container == "{}" {
+		kinds = strings.Split(valType, ",")
+		if len(kinds) != 2 {
+			return "", nil, ErrUnsupportedType
+		}
+	}
+	for i, kind := range kinds {
+		kinds[i] = strings.TrimSpace(kind)
+		switch kinds[i] {
+		case "int", "float", "bool", "string":
+		default:
+			return "", nil, ErrUnsupportedType
+		}
+	}
+	return container, kinds, nil
+}
@@ -0,0 +1,72 @@
+package stress
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestIntBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int64
+	}{
+		{name: "small", min: -3, max: 3},
+		{name: "single value", min: 7, max: 7},
+		{name: "wider than int64", min: -9e18, max: 9e18},
+		{name: "positive half", min: 0, max: math.MaxInt64},
+		{name: "every int64", min: math.MinInt64, max: math.MaxInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{rand: rand.New(rand.NewSource(1))}
+			for i := 0; i < 1000; i++ {
+				if v := g.intBetween(tt.min, tt.max); v < tt.min || v > tt.max {
+					t.Fatalf("intBetween(%v, %v) = %v", tt.min, tt.max, v)
+				}
+			}
+		})
+	}
+}
+
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  int64
+	}{
+		{value: 2.9, want: 2},
+		{value: -2.9, want: -2},
+		{value: 1e19, want: math.MaxInt64},
+		{value: -1e19, want: math.MinInt64},
+		{value: math.Inf(1), want: math.MaxInt64},
+	}
+	for _, tt := range tests {
+		if got := toInt(tt.value); got != tt.want {
+			t.Errorf("toInt(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNewValidatesArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     Arg
+		wantErr bool
+	}{
+		{name: "defaults", arg: Arg{}},
+		{name: "bounds", arg: Arg{Size: []int{0, 5}, Length: []int{1, 1}, Range: []float64{-1e19, 1e19}}},
+		{name: "size with one bound", arg: Arg{Size: []int{3}}, wantErr: true},
+		{name: "inverted size", arg: Arg{Size: []int{5, 2}}, wantErr: true},
+		{name: "negative length", arg: Arg{Length: []int{-1, 2}}, wantErr: true},
+		{name: "inverted range", arg: Arg{Range: []float64{3, 1}}, wantErr: true},
+		{name: "nan range", arg: Arg{Range: []float64{math.NaN(), 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New([]string{"[]int"}, []Arg{tt.arg}, 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}